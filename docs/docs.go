@@ -15,95 +15,2682 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/hotels/{hotel_id}/reviews": {
+        "/admin/data-quality": {
             "get": {
-                "description": "Returns average rating and paginated reviews for a hotel",
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "For each day (UTC, by ingestion time) and platform, returns how many reviews were\nstored, how many of those carry quality flags, and how often each rule was broken,\nincluding records that were rejected or quarantined. Also lists the active rules.\nRequires a token with the analyst role.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reviews"
+                    "admin"
                 ],
-                "summary": "Get hotel reviews and overall rating",
+                "summary": "Summarize data quality per platform and day",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "7 days ago",
+                        "description": "First day, YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "today",
+                        "description": "Last day, YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only this platform",
+                        "name": "platform",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DataQualityReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/hotel-matches": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Returns match candidates linking a platform listing to an existing canonical hotel\nRequires a token with the hotel_admin role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List proposed cross-platform hotel matches",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "proposed",
+                        "description": "proposed, confirmed or rejected",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.HotelMatchCandidate"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/hotel-matches/{id}/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Moves the listing and its reviews to the proposed canonical hotel\nRequires a token with the hotel_admin role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Confirm a proposed hotel match",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Match ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/hotel-matches/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Requires a token with the hotel_admin role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reject a proposed hotel match",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Match ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/hotels/{id}/listings": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Requires a token with the hotel_admin role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List a hotel's platform listings",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "Hotel ID",
-                        "name": "hotel_id",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.HotelPlatformListing"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/hotels/{id}/merge": {
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Moves every listing and review of the given hotels into this one and deletes them\nRequires a token with the hotel_admin role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Merge canonical hotels",
+                "parameters": [
                     {
                         "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
+                        "description": "Hotel ID to keep",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Hotels to merge in",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MergeHotelsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/hotels/{id}/split": {
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Creates a new canonical hotel for the given listings and moves their reviews to it\nRequires a token with the hotel_admin role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Split listings off a canonical hotel",
+                "parameters": [
                     {
                         "type": "integer",
-                        "default": 20,
-                        "description": "Reviews per page",
-                        "name": "limit",
+                        "description": "Hotel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Listings to split off",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SplitHotelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Hotel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/hotels/{id}/timezone": {
+            "put": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Sets the IANA timezone review dates are bucketed into days in, e.g. \"Asia/Ho_Chi_Minh\",\nand moves the hotel's existing review dates to it. An empty timezone clears it.\nRequires a token with the hotel_admin role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Set a hotel's timezone",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hotel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Timezone",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SetTimezoneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Hotel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/moderation-actions": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Returns moderation status changes, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List the moderation audit trail",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Only this review",
+                        "name": "review_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only changes by this actor",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Actions per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ModerationActionPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reviews/moderation": {
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Moves up to 500 reviews to visible, pending, hidden or removed. A reason is required\nfor every status but visible. Each change is recorded with the token's actor, and\nthe rating summaries of hotels whose visible reviews changed are recomputed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Change the moderation status of reviews",
+                "parameters": [
+                    {
+                        "description": "Reviews, status and reason",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ModerateReviewsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/moderation.Result"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reviews/{id}/original": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Returns the title and text as the provider sent them, before personal data was\nredacted. Requires a token with the pii_reader role. Every read is logged.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get a review's original, unredacted text",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewOriginal"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/sentiment-mismatches": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Reports reviews with negative text and a normalized rating at or above high_rating,\nor positive text and a rating at or below low_rating, strongest disagreement first.\nRequires a token with the analyst role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List reviews whose text sentiment disagrees with their rating",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Only this hotel",
+                        "name": "hotel_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only this platform",
+                        "name": "platform",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "default": 7,
+                        "description": "Normalized rating negative text is reported at or above",
+                        "name": "high_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "default": 4,
+                        "description": "Normalized rating positive text is reported at or below",
+                        "name": "low_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
                         "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Reviews per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SentimentMismatchReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/suspicious-reviews": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Reports reviews whose suspicion score is at or above min_score, most suspicious first,\nwith the fraud rules that flagged them: rating_burst, template_text,\nextreme_rating_short_text, single_review_account and repeat_reviewer.\nRequires a token with the moderator role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List reviews that look like spam or fraud",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Only this hotel",
+                        "name": "hotel_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only this platform",
+                        "name": "platform",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only reviews flagged by this rule",
+                        "name": "reason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Lowest suspicion score reported, 0 to 1; SUSPICION_THRESHOLD by default",
+                        "name": "min_score",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Reviews per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuspiciousReviewReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/hotels": {
+            "get": {
+                "description": "Lists hotels with their pre-aggregated ratings, read from the rating summaries\nrather than the reviews. q matches the hotel name, ignoring case, punctuation and\nwords like \"hotel\". Hotels without reviews count as rated 0.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hotels"
+                ],
+                "summary": "Search and list hotels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name search",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only hotels listed on this platform",
+                        "name": "platform",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Lowest normalized average rating, 0 to 10",
+                        "name": "min_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Fewest reviews",
+                        "name": "min_reviews",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "rating",
+                        "description": "rating, reviews or updated",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "desc",
+                        "description": "asc or desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Hotels per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HotelList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/hotels/{hotel_id}/aspects": {
+            "get": {
+                "description": "Counts the sentences mentioning each aspect (staff, room, noise, breakfast, ...) in\nreviews dated within the window, by polarity, with the net sentiment and the latest\ncomplaints. Aspects with the worst net sentiment come first. Days are calendar days\nat the hotel.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Get what a hotel's reviews say about each aspect",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hotel ID",
+                        "name": "hotel_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "89 days before to",
+                        "description": "First day, YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "today at the hotel",
+                        "description": "Last day, YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count each cluster of near-duplicate reviews once",
+                        "name": "dedupe",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HotelAspectSentiment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/hotels/{hotel_id}/ratings/breakdown": {
+            "get": {
+                "description": "Returns the overall average and the average of each aspect (cleanliness, location,\nstaff, value, facilities), all on the normalized 0–10 scale. Each aspect has its\nown count, since not every review scores every aspect.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Get a hotel's rating breakdown by aspect",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hotel ID",
+                        "name": "hotel_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count each cluster of near-duplicate reviews once",
+                        "name": "dedupe",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RatingsBreakdown"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/hotels/{hotel_id}/ratings/trend": {
+            "get": {
+                "description": "Returns the number of reviews and their average normalized rating per day. Days are\ncalendar days at the hotel, in its timezone when one is set. Reviews without a\nusable date are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Get a hotel's daily rating trend",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hotel ID",
+                        "name": "hotel_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "29 days before to",
+                        "description": "First day, YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "today at the hotel",
+                        "description": "Last day, YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count each cluster of near-duplicate reviews once",
+                        "name": "dedupe",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RatingTrend"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/hotels/{hotel_id}/reviews": {
+            "get": {
+                "description": "Returns average rating and paginated reviews for a hotel. The average is on the\nnormalized 0–10 scale; each review carries both its raw and normalized rating and\nany per-aspect sub-ratings the platform sent. Reviews include the hotel's reply, and\nthe response carries the hotel's response rate and median response time. Filters\nnarrow the reviews listed; the summary covers every review unless filtered_summary\nis set. Dates are calendar days at the hotel. Pages can be walked with page or,\nfaster and stable while reviews arrive, by passing next_cursor back as cursor.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Get hotel reviews and overall rating",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hotel ID",
+                        "name": "hotel_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Reviews per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page; page is ignored with it",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count the reviews matching the filters",
+                        "name": "include_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "newest",
+                        "description": "newest, oldest, highest or lowest",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only reviews in this ISO 639-1 language, or und for undetermined",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only reviews from this platform",
+                        "name": "platform",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only reviewers from this country, by ISO 3166-1 alpha-2 code or name",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only this traveler group, e.g. Couple",
+                        "name": "review_group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only this room type",
+                        "name": "room_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Lowest normalized rating, 0 to 10",
+                        "name": "min_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Highest normalized rating, 0 to 10",
+                        "name": "max_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First review day, YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last review day, YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only the last this many days up to today, instead of from and to",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only reviews with (true) or without (false) text",
+                        "name": "has_text",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Apply the filters to the summary too",
+                        "name": "filtered_summary",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count each cluster of near-duplicate reviews once in the summary, aspects and language breakdown",
+                        "name": "dedupe",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Leave out reviews scored at or above the suspicion threshold",
+                        "name": "exclude_suspicious",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/hotels/{id}/reviews/{reviewId}/response": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Returns the reply the platform published and the local draft, whichever exist, so\na draft can be reviewed next to what guests see. Requires a token with the responder\nrole.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List a review's replies, including the local draft",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hotel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "reviewId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ResponseDetail"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Stores the hotel's draft reply to a review, replacing any earlier draft. The reply the\nplatform publishes is ingested separately and is not changed. Personal data is\nredacted from the text as it is from reviews. Drafts are not shown on\npublic endpoints. The author defaults to the token's actor. Requires a token with the\nresponder role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Write a local draft reply to a review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hotel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "reviewId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reply",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReviewReplyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResponseDetail"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platforms/{platform}/hotels/resolve": {
+            "post": {
+                "description": "Maps up to 500 of a platform's hotel IDs to our hotels and their rating summaries.\nIDs we have no listing for resolve to a null hotel.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hotels"
+                ],
+                "summary": "Resolve provider hotel IDs in bulk",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Platform name, case-insensitive",
+                        "name": "platform",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The platform's hotel IDs",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResolveHotelsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ResolvedHotel"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platforms/{platform}/hotels/{externalId}": {
+            "get": {
+                "description": "Resolves a platform's own hotel ID, e.g. Agoda 10984, to our hotel and returns it\nwith its rating summary.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hotels"
+                ],
+                "summary": "Get a hotel by the provider's hotel ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Platform name, case-insensitive",
+                        "name": "platform",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The platform's hotel ID",
+                        "name": "externalId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HotelListItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platforms/{platform}/hotels/{externalId}/reviews": {
+            "get": {
+                "description": "Resolves a platform's own hotel ID to our hotel and returns the same response as\nGET /hotels/{hotel_id}/reviews, reviews from every platform included, and takes the\nsame query parameters.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Get a hotel's reviews by the provider's hotel ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Platform name, case-insensitive",
+                        "name": "platform",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The platform's hotel ID",
+                        "name": "externalId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Reviews per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only reviews in this ISO 639-1 language, or und for undetermined",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count each cluster of near-duplicate reviews once in the summary, aspects and language breakdown",
+                        "name": "dedupe",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Leave out reviews scored at or above the suspicion threshold",
+                        "name": "exclude_suspicious",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/search": {
+            "get": {
+                "description": "Full-text search across every hotel's visible reviews, best matches first. q takes\nweb search syntax: \"quoted phrases\", OR and -excluded words. Words are stemmed in\nthe review's language, so \"shuttles\" finds \"shuttle\"; lang searches one language\nonly. Snippets and titles mark matches with \u003cmark\u003e and are otherwise HTML-escaped.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Search review titles and text",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only this hotel",
+                        "name": "hotel_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only this platform",
+                        "name": "platform",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only reviews in this ISO 639-1 language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First review day, YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last review day, YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only the last this many days, instead of from and to",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Results per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewSearchResults"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.MergeHotelsRequest": {
+            "type": "object",
+            "properties": {
+                "hotel_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handlers.ModerateReviewsRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "review_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "status": {
+                    "description": "visible, pending, hidden or removed",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ResolveHotelsRequest": {
+            "type": "object",
+            "properties": {
+                "external_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handlers.ReviewReplyRequest": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.SetTimezoneRequest": {
+            "type": "object",
+            "properties": {
+                "timezone": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.SplitHotelRequest": {
+            "type": "object",
+            "properties": {
+                "listing_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AggregatedHotelReview": {
+            "type": "object",
+            "properties": {
+                "averageRating": {
+                    "type": "number"
+                },
+                "hotelID": {
+                    "type": "integer"
+                },
+                "hotelName": {
+                    "type": "string"
+                },
+                "reviewCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AspectRating": {
+            "type": "object",
+            "properties": {
+                "aspect": {
+                    "type": "string"
+                },
+                "average_rating": {
+                    "type": "number"
+                },
+                "total_ratings": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AspectSentiment": {
+            "type": "object",
+            "properties": {
+                "aspect": {
+                    "type": "string"
+                },
+                "average_score": {
+                    "type": "number"
+                },
+                "complaints": {
+                    "description": "The latest sentences complaining about the aspect.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "mentions": {
+                    "type": "integer"
+                },
+                "negative": {
+                    "type": "integer"
+                },
+                "net_sentiment": {
+                    "description": "(positive - negative) / mentions, -1 to 1",
+                    "type": "number"
+                },
+                "neutral": {
+                    "type": "integer"
+                },
+                "positive": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DailyRating": {
+            "type": "object",
+            "properties": {
+                "average_rating": {
+                    "type": "number"
+                },
+                "day": {
+                    "description": "YYYY-MM-DD at the hotel",
+                    "type": "string"
+                },
+                "review_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DataQualityDay": {
+            "type": "object",
+            "properties": {
+                "day": {
+                    "type": "string"
+                },
+                "flagged_reviews": {
+                    "description": "stored with quality flags",
+                    "type": "integer"
+                },
+                "platform": {
+                    "type": "string"
+                },
+                "quarantined": {
+                    "type": "integer"
+                },
+                "rejected": {
+                    "type": "integer"
+                },
+                "reviews": {
+                    "description": "stored that day",
+                    "type": "integer"
+                },
+                "violations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RuleViolationCount"
+                    }
+                }
+            }
+        },
+        "models.DataQualityReport": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DataQualityDay"
+                    }
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.QualityRuleInfo"
+                    }
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Hotel": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "externalID": {
+                    "description": "ExternalID is the provider hotelId of the first listing seen for this\nhotel. It is not unique across providers; resolve through listings.",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "normalizedName": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "description": "Timezone is the hotel's IANA timezone, e.g. \"Asia/Ho_Chi_Minh\". Review\ndates are bucketed into days in it; empty means unknown.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.HotelAspectSentiment": {
+            "type": "object",
+            "properties": {
+                "aspects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AspectSentiment"
+                    }
+                },
+                "from": {
+                    "description": "YYYY-MM-DD at the hotel",
+                    "type": "string"
+                },
+                "hotel_id": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HotelList": {
+            "type": "object",
+            "properties": {
+                "hotels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HotelListItem"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HotelListItem": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "average_rating": {
+                    "description": "normalized 0–10",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_updated": {
+                    "description": "null until the hotel has a summary",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "platforms": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "total_reviews": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HotelMatchCandidate": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "hotelID": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "listingID": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "description": "comma-separated signals, e.g. \"name,geo\"",
+                    "type": "string"
+                },
+                "score": {
+                    "description": "0–1",
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HotelPlatformListing": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "externalID": {
+                    "type": "integer"
+                },
+                "hotelID": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "platformID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.LanguageCount": {
+            "type": "object",
+            "properties": {
+                "language": {
+                    "description": "ISO 639-1, or und",
+                    "type": "string"
+                },
+                "review_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ModerationAction": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "from_status": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "review_id": {
+                    "type": "integer"
+                },
+                "to_status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ModerationActionPage": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ModerationAction"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.QualityRuleInfo": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "platforms": {
+                    "description": "per-platform action overrides",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.RatingTrend": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DailyRating"
+                    }
+                },
+                "hotel_id": {
+                    "type": "integer"
+                },
+                "timezone": {
+                    "description": "empty if the hotel has none set",
+                    "type": "string"
+                }
+            }
+        },
+        "models.RatingsBreakdown": {
+            "type": "object",
+            "properties": {
+                "aspects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AspectRating"
+                    }
+                },
+                "average_rating": {
+                    "type": "number"
+                },
+                "hotel_id": {
+                    "type": "integer"
+                },
+                "total_reviews": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ResolvedHotel": {
+            "type": "object",
+            "properties": {
+                "external_id": {
+                    "type": "integer"
+                },
+                "hotel": {
+                    "$ref": "#/definitions/models.HotelListItem"
+                },
+                "hotel_id": {
+                    "type": "integer"
+                },
+                "platform": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ResponseDetail": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "response_date": {
+                    "description": "null if the platform sent no usable date",
+                    "type": "string"
+                },
+                "source": {
+                    "description": "platform or local",
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ResponseMetrics": {
+            "type": "object",
+            "properties": {
+                "median_response_hours": {
+                    "type": "number"
+                },
+                "responded_reviews": {
+                    "type": "integer"
+                },
+                "response_rate": {
+                    "description": "0–1",
+                    "type": "number"
+                },
+                "total_reviews": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ReviewDetail": {
+            "type": "object",
+            "properties": {
+                "country_code": {
+                    "description": "ISO 3166-1 alpha-2, null if unrecognized",
+                    "type": "string"
+                },
+                "country_name": {
+                    "type": "string"
+                },
+                "duplicate_cluster_id": {
+                    "description": "null unless near-duplicates were found",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "language": {
+                    "description": "ISO 639-1, \"\" if undetermined",
+                    "type": "string"
+                },
+                "length_of_stay": {
+                    "type": "integer"
+                },
+                "normalized_rating": {
+                    "type": "number"
+                },
+                "platform": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "rating_scale_max": {
+                    "type": "number"
+                },
+                "response": {
+                    "$ref": "#/definitions/models.ResponseDetail"
+                },
+                "review_date": {
+                    "description": "null if the provider's date was missing or unparseable",
+                    "type": "string"
+                },
+                "review_group_name": {
+                    "type": "string"
+                },
+                "review_local_date": {
+                    "description": "YYYY-MM-DD at the hotel",
+                    "type": "string"
+                },
+                "review_text": {
+                    "type": "string"
+                },
+                "review_title": {
+                    "type": "string"
+                },
+                "reviewer_name": {
+                    "type": "string"
+                },
+                "room_type_name": {
+                    "type": "string"
+                },
+                "sentiment_label": {
+                    "description": "positive, neutral or negative",
+                    "type": "string"
+                },
+                "sentiment_score": {
+                    "description": "-1 to 1, null if unscored",
+                    "type": "number"
+                },
+                "stay_date": {
+                    "type": "string"
+                },
+                "sub_ratings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SubRatingDetail"
                     }
-                ],
+                },
+                "suspicion_reasons": {
+                    "description": "comma-separated fraud rules, \"\" if none fired",
+                    "type": "string"
+                },
+                "suspicion_score": {
+                    "description": "0 to 1",
+                    "type": "number"
+                }
+            }
+        },
+        "models.ReviewOriginal": {
+            "type": "object",
+            "properties": {
+                "hotel_review_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "pii_kinds": {
+                    "description": "kinds of personal data redacted from the served text",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "review_text": {
+                    "type": "string"
+                },
+                "review_title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReviewPagination": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "description": "pass as cursor for the next page, null on the last",
+                    "type": "string"
+                },
+                "page": {
+                    "description": "omitted when paging by cursor",
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "with include_total only",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ReviewResponse": {
+            "type": "object",
+            "properties": {
+                "aspects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AspectRating"
+                    }
+                },
+                "hotel": {
+                    "$ref": "#/definitions/models.AggregatedHotelReview"
+                },
+                "languages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LanguageCount"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/models.ReviewPagination"
+                },
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.ReviewResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
+                    "$ref": "#/definitions/models.ResponseMetrics"
+                },
+                "reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReviewDetail"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.AggregatedHotelReview": {
+        },
+        "models.ReviewSearchHit": {
             "type": "object",
             "properties": {
-                "averageRating": {
+                "hotel_id": {
+                    "type": "integer"
+                },
+                "hotel_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "normalized_rating": {
                     "type": "number"
                 },
-                "hotelID": {
+                "platform": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "number"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "review_date": {
+                    "type": "string"
+                },
+                "snippet": {
+                    "description": "HTML fragments of the text around the matches",
+                    "type": "string"
+                },
+                "title_highlight": {
+                    "description": "HTML, matches in \u003cmark\u003e",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReviewSearchResults": {
+            "type": "object",
+            "properties": {
+                "limit": {
                     "type": "integer"
                 },
-                "hotelName": {
+                "page": {
+                    "type": "integer"
+                },
+                "query": {
                     "type": "string"
                 },
-                "reviewCount": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReviewSearchHit"
+                    }
+                },
+                "total": {
                     "type": "integer"
                 }
             }
         },
-        "models.ErrorResponse": {
+        "models.RuleViolationCount": {
             "type": "object",
             "properties": {
-                "error": {
+                "action": {
+                    "type": "string"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "rule": {
                     "type": "string"
                 }
             }
         },
-        "models.ReviewDetail": {
+        "models.SentimentMismatch": {
             "type": "object",
             "properties": {
-                "country_name": {
+                "hotel_id": {
+                    "type": "integer"
+                },
+                "hotel_name": {
+                    "type": "string"
+                },
+                "hotel_review_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "mismatch": {
+                    "description": "negative_text_high_rating or positive_text_low_rating",
+                    "type": "string"
+                },
+                "normalized_rating": {
+                    "type": "number"
+                },
+                "platform": {
                     "type": "string"
                 },
                 "rating": {
                     "type": "number"
                 },
-                "review_date": {
+                "review_text": {
                     "type": "string"
                 },
-                "review_group_name": {
+                "review_title": {
+                    "type": "string"
+                },
+                "sentiment_label": {
+                    "type": "string"
+                },
+                "sentiment_score": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.SentimentMismatchReport": {
+            "type": "object",
+            "properties": {
+                "high_rating": {
+                    "type": "number"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "low_rating": {
+                    "type": "number"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SentimentMismatch"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SubRatingDetail": {
+            "type": "object",
+            "properties": {
+                "aspect": {
+                    "type": "string"
+                },
+                "normalized_rating": {
+                    "type": "number"
+                },
+                "rating": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.SuspiciousReview": {
+            "type": "object",
+            "properties": {
+                "duplicate_cluster_id": {
+                    "type": "integer"
+                },
+                "hotel_id": {
+                    "type": "integer"
+                },
+                "hotel_name": {
+                    "type": "string"
+                },
+                "hotel_review_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "normalized_rating": {
+                    "type": "number"
+                },
+                "platform": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "review_date": {
                     "type": "string"
                 },
                 "review_text": {
@@ -112,25 +2699,76 @@ const docTemplate = `{
                 "review_title": {
                     "type": "string"
                 },
-                "room_type_name": {
-                    "type": "string"
+                "reviewer_profile_id": {
+                    "type": "integer"
+                },
+                "suspicion_reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "suspicion_score": {
+                    "description": "0 to 1",
+                    "type": "number"
                 }
             }
         },
-        "models.ReviewResponse": {
+        "models.SuspiciousReviewReport": {
             "type": "object",
             "properties": {
-                "hotel": {
-                    "$ref": "#/definitions/models.AggregatedHotelReview"
+                "limit": {
+                    "type": "integer"
+                },
+                "min_score": {
+                    "type": "number"
+                },
+                "page": {
+                    "type": "integer"
                 },
                 "reviews": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.ReviewDetail"
+                        "$ref": "#/definitions/models.SuspiciousReview"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "moderation.Result": {
+            "type": "object",
+            "properties": {
+                "not_found": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "unchanged": {
+                    "description": "already in the requested status",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "updated": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
                     }
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "AdminToken": {
+            "description": "\"Bearer \u003ctoken\u003e\", with a token from ADMIN_TOKENS",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
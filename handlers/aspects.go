@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"review-system/internal/sentiment"
+	"review-system/models"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// complaintsPerAspect is how many complaining sentences each aspect lists.
+const complaintsPerAspect = 3
+
+// GetHotelAspects godoc
+// @Summary Get what a hotel's reviews say about each aspect
+// @Description Counts the sentences mentioning each aspect (staff, room, noise, breakfast, ...) in
+// @Description reviews dated within the window, by polarity, with the net sentiment and the latest
+// @Description complaints. Aspects with the worst net sentiment come first. Days are calendar days
+// @Description at the hotel.
+// @Tags reviews
+// @Produce json
+// @Param hotel_id path int true "Hotel ID"
+// @Param from query string false "First day, YYYY-MM-DD" default(89 days before to)
+// @Param to query string false "Last day, YYYY-MM-DD" default(today at the hotel)
+// @Param dedupe query bool false "Count each cluster of near-duplicate reviews once"
+// @Success 200 {object} models.HotelAspectSentiment
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hotels/{hotel_id}/aspects [get]
+func GetHotelAspects(c echo.Context) error {
+	hotelID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid hotel id"})
+	}
+	db := models.GetDB()
+
+	var hotel models.Hotel
+	if err := db.First(&hotel, hotelID).Error; err == gorm.ErrRecordNotFound {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": "Hotel not found"})
+	} else if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch hotel"})
+	}
+
+	from, to, err := localDateWindow(c, hotel, 90)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	dedupe, err := dedupeFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+
+	var rows []models.AspectSentiment
+	if err := db.Raw(`
+        SELECT m.aspect, COUNT(*) AS mentions,
+               COUNT(*) FILTER (WHERE m.polarity = ?) AS positive,
+               COUNT(*) FILTER (WHERE m.polarity = ?) AS neutral,
+               COUNT(*) FILTER (WHERE m.polarity = ?) AS negative,
+               ROUND(AVG(m.score)::numeric, 3) AS average_score
+        FROM review_aspect_mentions m
+        JOIN reviews r ON r.id = m.review_id
+        WHERE r.hotel_id = ? AND r.review_local_date BETWEEN ? AND ? AND `+models.VisibleOnly+` `+dedupe+`
+        GROUP BY m.aspect
+    `, sentiment.Positive, sentiment.Neutral, sentiment.Negative, hotelID, from, to).Scan(&rows).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch aspect mentions"})
+	}
+
+	var complaints []struct {
+		Aspect   string
+		Sentence string
+	}
+	if err := db.Raw(`
+        SELECT aspect, sentence FROM (
+            SELECT m.aspect, m.sentence,
+                   ROW_NUMBER() OVER (PARTITION BY m.aspect ORDER BY r.review_date DESC NULLS LAST, m.id DESC) AS n
+            FROM review_aspect_mentions m
+            JOIN reviews r ON r.id = m.review_id
+            WHERE r.hotel_id = ? AND r.review_local_date BETWEEN ? AND ? AND `+models.VisibleOnly+` AND m.polarity = ? `+dedupe+`
+        ) latest
+        WHERE n <= ?
+        ORDER BY aspect, n
+    `, hotelID, from, to, sentiment.Negative, complaintsPerAspect).Scan(&complaints).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch complaints"})
+	}
+	byAspect := make(map[string][]string)
+	for _, row := range complaints {
+		byAspect[row.Aspect] = append(byAspect[row.Aspect], row.Sentence)
+	}
+
+	for i := range rows {
+		r := &rows[i]
+		r.NetSentiment = math.Round(float64(r.Positive-r.Negative)/float64(r.Mentions)*1000) / 1000
+		r.Complaints = byAspect[r.Aspect]
+		if r.Complaints == nil {
+			r.Complaints = []string{}
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].NetSentiment != rows[j].NetSentiment {
+			return rows[i].NetSentiment < rows[j].NetSentiment
+		}
+		if rows[i].Mentions != rows[j].Mentions {
+			return rows[i].Mentions > rows[j].Mentions
+		}
+		return rows[i].Aspect < rows[j].Aspect
+	})
+
+	result := models.HotelAspectSentiment{
+		HotelID: hotel.ID,
+		From:    from.Format("2006-01-02"),
+		To:      to.Format("2006-01-02"),
+		Aspects: rows,
+	}
+	if result.Aspects == nil {
+		result.Aspects = []models.AspectSentiment{}
+	}
+	return c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Admin roles, granted to bearer tokens through ADMIN_TOKENS.
+const (
+	// RolePIIReader may read the original, unredacted review text.
+	RolePIIReader = "pii_reader"
+	// RoleModerator may change the moderation status of reviews.
+	RoleModerator = "moderator"
+	// RoleHotelAdmin may match, merge and split canonical hotels.
+	RoleHotelAdmin = "hotel_admin"
+	// RoleResponder may read and write a hotel's draft replies to reviews.
+	RoleResponder = "responder"
+	// RoleAnalyst may read the data quality and review analysis reports.
+	RoleAnalyst = "analyst"
+)
+
+// actorKey is the context key RequireRole stores the token's actor under.
+const actorKey = "admin_actor"
+
+type roleToken struct {
+	role  string
+	actor string
+	token []byte
+}
+
+var (
+	roleTokensOnce sync.Once
+	roleTokens     []roleToken
+)
+
+// loadRoleTokens reads ADMIN_TOKENS, a comma-separated list of role:token
+// pairs such as "pii_reader:s3cret", or role:actor:token triples such as
+// "moderator:alice:s3cret" naming who holds the token in audit trails. A
+// token listed under several roles has all of them. It is read on first use
+// so a .env file has been loaded.
+func loadRoleTokens() []roleToken {
+	roleTokensOnce.Do(func() {
+		for _, entry := range strings.Split(os.Getenv("ADMIN_TOKENS"), ",") {
+			parts := strings.SplitN(strings.TrimSpace(entry), ":", 3)
+			rt := roleToken{role: parts[0], actor: parts[0]}
+			switch len(parts) {
+			case 2:
+				rt.token = []byte(parts[1])
+			case 3:
+				rt.actor, rt.token = parts[1], []byte(parts[2])
+			}
+			if rt.role != "" && rt.actor != "" && len(rt.token) > 0 {
+				roleTokens = append(roleTokens, rt)
+			}
+		}
+	})
+	return roleTokens
+}
+
+// Actor names the holder of the token a RequireRole route was called with.
+func Actor(c echo.Context) string {
+	actor, _ := c.Get(actorKey).(string)
+	return actor
+}
+
+// RequireRole only lets through requests whose "Authorization: Bearer" token
+// has role. Without any token configured for the role, every request is
+// refused.
+func RequireRole(role string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if !ok || token == "" {
+				return c.JSON(http.StatusUnauthorized, echo.Map{"error": "Missing bearer token"})
+			}
+			known := false
+			for _, rt := range loadRoleTokens() {
+				if subtle.ConstantTimeCompare(rt.token, []byte(token)) != 1 {
+					continue
+				}
+				if rt.role == role {
+					c.Set(actorKey, rt.actor)
+					return next(c)
+				}
+				known = true
+			}
+			if known {
+				return c.JSON(http.StatusForbidden, echo.Map{"error": "Token lacks the " + role + " role"})
+			}
+			return c.JSON(http.StatusUnauthorized, echo.Map{"error": "Invalid token"})
+		}
+	}
+}
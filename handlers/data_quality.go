@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"review-system/internal/ingestion"
+	"review-system/internal/quality"
+	"review-system/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetDataQuality godoc
+// @Summary Summarize data quality per platform and day
+// @Description For each day (UTC, by ingestion time) and platform, returns how many reviews were
+// @Description stored, how many of those carry quality flags, and how often each rule was broken,
+// @Description including records that were rejected or quarantined. Also lists the active rules.
+// @Description Requires a token with the analyst role.
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Param from query string false "First day, YYYY-MM-DD" default(7 days ago)
+// @Param to query string false "Last day, YYYY-MM-DD" default(today)
+// @Param platform query string false "Only this platform"
+// @Success 200 {object} models.DataQualityReport
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/data-quality [get]
+func GetDataQuality(c echo.Context) error {
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	from := to.AddDate(0, 0, -6)
+	var err error
+	if v := c.QueryParam("from"); v != "" {
+		if from, err = time.Parse("2006-01-02", v); err != nil {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid from date, want YYYY-MM-DD"})
+		}
+	}
+	if v := c.QueryParam("to"); v != "" {
+		if to, err = time.Parse("2006-01-02", v); err != nil {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid to date, want YYYY-MM-DD"})
+		}
+	}
+	platform := c.QueryParam("platform")
+	db := models.GetDB()
+
+	type key struct{ day, platform string }
+	days := make(map[key]*models.DataQualityDay)
+	dayFor := func(day time.Time, platform string) *models.DataQualityDay {
+		k := key{day.Format("2006-01-02"), platform}
+		if days[k] == nil {
+			days[k] = &models.DataQualityDay{Day: k.day, Platform: platform, Violations: []models.RuleViolationCount{}}
+		}
+		return days[k]
+	}
+
+	var ingested []struct {
+		Day      time.Time
+		Platform string
+		Reviews  int64
+		Flagged  int64
+	}
+	query := db.Table("reviews r").
+		Select("DATE(r.created_at) AS day, p.name AS platform, COUNT(*) AS reviews, COUNT(*) FILTER (WHERE r.quality_flags <> '') AS flagged").
+		Joins("JOIN platforms p ON p.id = r.platform_id").
+		Where("r.created_at >= ? AND r.created_at < ?", from, to.AddDate(0, 0, 1))
+	if platform != "" {
+		query = query.Where("LOWER(p.name) = LOWER(?)", platform)
+	}
+	if err := query.Group("DATE(r.created_at), p.name").Scan(&ingested).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to count reviews"})
+	}
+	for _, row := range ingested {
+		d := dayFor(row.Day, row.Platform)
+		d.Reviews, d.FlaggedReviews = row.Reviews, row.Flagged
+	}
+
+	var counts []models.QualityViolationCount
+	query = db.Where("day BETWEEN ? AND ?", from, to)
+	if platform != "" {
+		query = query.Where("LOWER(platform) = LOWER(?)", platform)
+	}
+	if err := query.Order("rule").Find(&counts).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch violation counts"})
+	}
+	for _, row := range counts {
+		d := dayFor(row.Day, row.Platform)
+		d.Violations = append(d.Violations, models.RuleViolationCount{Rule: row.Rule, Action: row.Action, Count: row.Count})
+	}
+
+	// A record breaking several rules appears under each of them above, so
+	// rejected and quarantined records are counted once per record.
+	var outcomes []models.QualityOutcomeCount
+	query = db.Where("day BETWEEN ? AND ?", from, to)
+	if platform != "" {
+		query = query.Where("LOWER(platform) = LOWER(?)", platform)
+	}
+	if err := query.Find(&outcomes).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch outcome counts"})
+	}
+	for _, row := range outcomes {
+		d := dayFor(row.Day, row.Platform)
+		switch row.Action {
+		case quality.Reject.String():
+			d.Rejected += row.Count
+		case quality.Quarantine.String():
+			d.Quarantined += row.Count
+		}
+	}
+
+	report := models.DataQualityReport{Days: []models.DataQualityDay{}}
+	for _, d := range days {
+		report.Days = append(report.Days, *d)
+	}
+	sort.Slice(report.Days, func(i, j int) bool {
+		a, b := report.Days[i], report.Days[j]
+		if a.Day != b.Day {
+			return a.Day > b.Day
+		}
+		return a.Platform < b.Platform
+	})
+
+	for _, rule := range ingestion.QualityEngine.Rules() {
+		info := models.QualityRuleInfo{
+			Name:        rule.Name,
+			Description: rule.Description,
+			Action:      rule.Action.String(),
+			Platforms:   map[string]string{},
+		}
+		for p, a := range rule.Platforms {
+			info.Platforms[strings.ToLower(p)] = a.String()
+		}
+		report.Rules = append(report.Rules, info)
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
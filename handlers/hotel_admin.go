@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"review-system/internal/hotels"
+	"review-system/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+type MergeHotelsRequest struct {
+	HotelIDs []uint `json:"hotel_ids"`
+}
+
+type SetTimezoneRequest struct {
+	Timezone string `json:"timezone"`
+}
+
+type SplitHotelRequest struct {
+	ListingIDs []uint `json:"listing_ids"`
+	Name       string `json:"name"`
+}
+
+// ListHotelMatches godoc
+// @Summary List proposed cross-platform hotel matches
+// @Description Returns match candidates linking a platform listing to an existing canonical hotel
+// @Description Requires a token with the hotel_admin role.
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Param status query string false "proposed, confirmed or rejected" default(proposed)
+// @Success 200 {array} models.HotelMatchCandidate
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/hotel-matches [get]
+func ListHotelMatches(c echo.Context) error {
+	status := c.QueryParam("status")
+	if status == "" {
+		status = models.MatchProposed
+	}
+
+	var matches []models.HotelMatchCandidate
+	if err := models.GetDB().Where("status = ?", status).Order("score DESC, id").Find(&matches).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch hotel matches"})
+	}
+	return c.JSON(http.StatusOK, matches)
+}
+
+// ConfirmHotelMatch godoc
+// @Summary Confirm a proposed hotel match
+// @Description Moves the listing and its reviews to the proposed canonical hotel
+// @Description Requires a token with the hotel_admin role.
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Param id path int true "Match ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/hotel-matches/{id}/confirm [post]
+func ConfirmHotelMatch(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid match id"})
+	}
+	if err := hotels.ConfirmMatch(models.GetDB(), uint(id)); err != nil {
+		return hotelAdminError(c, err)
+	}
+	return c.JSON(http.StatusOK, echo.Map{"status": models.MatchConfirmed})
+}
+
+// RejectHotelMatch godoc
+// @Summary Reject a proposed hotel match
+// @Description Requires a token with the hotel_admin role.
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Param id path int true "Match ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/hotel-matches/{id}/reject [post]
+func RejectHotelMatch(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid match id"})
+	}
+	if err := hotels.RejectMatch(models.GetDB(), uint(id)); err != nil {
+		return hotelAdminError(c, err)
+	}
+	return c.JSON(http.StatusOK, echo.Map{"status": models.MatchRejected})
+}
+
+// GetHotelListings godoc
+// @Summary List a hotel's platform listings
+// @Description Requires a token with the hotel_admin role.
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Param id path int true "Hotel ID"
+// @Success 200 {array} models.HotelPlatformListing
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/hotels/{id}/listings [get]
+func GetHotelListings(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid hotel id"})
+	}
+	var listings []models.HotelPlatformListing
+	if err := models.GetDB().Where("hotel_id = ?", id).Order("id").Find(&listings).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch listings"})
+	}
+	return c.JSON(http.StatusOK, listings)
+}
+
+// MergeHotels godoc
+// @Summary Merge canonical hotels
+// @Description Moves every listing and review of the given hotels into this one and deletes them
+// @Description Requires a token with the hotel_admin role.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param id path int true "Hotel ID to keep"
+// @Param body body MergeHotelsRequest true "Hotels to merge in"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/hotels/{id}/merge [post]
+func MergeHotels(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid hotel id"})
+	}
+	var req MergeHotelsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid request body"})
+	}
+	if err := hotels.Merge(models.GetDB(), uint(id), req.HotelIDs); err != nil {
+		return hotelAdminError(c, err)
+	}
+	return c.JSON(http.StatusOK, echo.Map{"hotel_id": id, "merged": req.HotelIDs})
+}
+
+// SplitHotel godoc
+// @Summary Split listings off a canonical hotel
+// @Description Creates a new canonical hotel for the given listings and moves their reviews to it
+// @Description Requires a token with the hotel_admin role.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param id path int true "Hotel ID"
+// @Param body body SplitHotelRequest true "Listings to split off"
+// @Success 201 {object} models.Hotel
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/hotels/{id}/split [post]
+func SplitHotel(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid hotel id"})
+	}
+	var req SplitHotelRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid request body"})
+	}
+	hotel, err := hotels.Split(models.GetDB(), uint(id), req.ListingIDs, req.Name)
+	if err != nil {
+		return hotelAdminError(c, err)
+	}
+	return c.JSON(http.StatusCreated, hotel)
+}
+
+// SetHotelTimezone godoc
+// @Summary Set a hotel's timezone
+// @Description Sets the IANA timezone review dates are bucketed into days in, e.g. "Asia/Ho_Chi_Minh",
+// @Description and moves the hotel's existing review dates to it. An empty timezone clears it.
+// @Description Requires a token with the hotel_admin role.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param id path int true "Hotel ID"
+// @Param body body SetTimezoneRequest true "Timezone"
+// @Success 200 {object} models.Hotel
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/hotels/{id}/timezone [put]
+func SetHotelTimezone(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid hotel id"})
+	}
+	var req SetTimezoneRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid request body"})
+	}
+	hotel, err := hotels.SetTimezone(models.GetDB(), uint(id), strings.TrimSpace(req.Timezone))
+	if err != nil {
+		return hotelAdminError(c, err)
+	}
+	return c.JSON(http.StatusOK, hotel)
+}
+
+func hotelAdminError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, hotels.ErrNotFound):
+		return c.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+	case errors.Is(err, hotels.ErrInvalid):
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	c.Logger().Errorf("hotel admin: %v", err)
+	return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Hotel update failed"})
+}
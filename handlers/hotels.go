@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"review-system/models"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// hotelListColumns selects a models.HotelListItem from hotels h joined to
+// their rating summaries s.
+const hotelListColumns = `h.id, h.name, h.address, h.timezone, COALESCE(s.total_reviews, 0) AS total_reviews,
+               COALESCE(s.average_rating, 0) AS average_rating, s.last_updated`
+
+// hotelSorts maps the sort parameter of GET /hotels to its ORDER BY.
+var hotelSorts = map[string]string{
+	"rating":  "average_rating",
+	"reviews": "total_reviews",
+	"updated": "last_updated",
+}
+
+// ListHotels godoc
+// @Summary Search and list hotels
+// @Description Lists hotels with their pre-aggregated ratings, read from the rating summaries
+// @Description rather than the reviews. q matches the hotel name, ignoring case, punctuation and
+// @Description words like "hotel". Hotels without reviews count as rated 0.
+// @Tags hotels
+// @Produce json
+// @Param q query string false "Name search"
+// @Param platform query string false "Only hotels listed on this platform"
+// @Param min_rating query number false "Lowest normalized average rating, 0 to 10"
+// @Param min_reviews query int false "Fewest reviews"
+// @Param sort query string false "rating, reviews or updated" default(rating)
+// @Param order query string false "asc or desc" default(desc)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Hotels per page" default(20)
+// @Success 200 {object} models.HotelList
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hotels [get]
+func ListHotels(c echo.Context) error {
+	page, limit := 1, 20
+	if p, err := strconv.Atoi(c.QueryParam("page")); err == nil && p > 0 {
+		page = p
+	}
+	if l, err := strconv.Atoi(c.QueryParam("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+
+	sort := c.QueryParam("sort")
+	if sort == "" {
+		sort = "rating"
+	}
+	column, ok := hotelSorts[sort]
+	if !ok {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "sort must be rating, reviews or updated"})
+	}
+	order := strings.ToLower(c.QueryParam("order"))
+	if order == "" {
+		order = "desc"
+	}
+	if order != "asc" && order != "desc" {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "order must be asc or desc"})
+	}
+
+	db := models.GetDB()
+	query := db.Table("hotels h").
+		Joins("LEFT JOIN hotel_ratings_summaries s ON s.hotel_id = h.id")
+	if q := strings.TrimSpace(c.QueryParam("q")); q != "" {
+		cond := "h.name ILIKE ?"
+		args := []interface{}{"%" + escapeLike(q) + "%"}
+		if normalized := models.NormalizeHotelName(q); normalized != "" {
+			cond += " OR h.normalized_name LIKE ?"
+			args = append(args, "%"+escapeLike(normalized)+"%")
+		}
+		query = query.Where(cond, args...)
+	}
+	if platform := c.QueryParam("platform"); platform != "" {
+		query = query.Where(`EXISTS (SELECT 1 FROM hotel_platform_listings l
+            JOIN platforms p ON p.id = l.platform_id
+            WHERE l.hotel_id = h.id AND LOWER(p.name) = LOWER(?))`, platform)
+	}
+	if v := c.QueryParam("min_rating"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 || f > models.NormalizedScaleMax {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": "min_rating must be between 0 and 10"})
+		}
+		query = query.Where("COALESCE(s.average_rating, 0) >= ?", f)
+	}
+	if v := c.QueryParam("min_reviews"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": "min_reviews must be a whole number"})
+		}
+		query = query.Where("COALESCE(s.total_reviews, 0) >= ?", n)
+	}
+
+	list := models.HotelList{Page: page, Limit: limit}
+	if err := query.Session(&gorm.Session{}).Count(&list.Total).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to count hotels"})
+	}
+	if err := query.
+		Select(hotelListColumns).
+		Order(column + " " + order + " NULLS LAST, h.id").
+		Limit(limit).Offset((page - 1) * limit).
+		Scan(&list.Hotels).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch hotels"})
+	}
+	if list.Hotels == nil {
+		list.Hotels = []models.HotelListItem{}
+	}
+	if err := attachPlatforms(db, list.Hotels); err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch platforms"})
+	}
+	return c.JSON(http.StatusOK, list)
+}
+
+// loadHotelListItems loads the given hotels with their rating summaries and
+// platforms, keyed by ID.
+func loadHotelListItems(db *gorm.DB, ids []uint) (map[uint]models.HotelListItem, error) {
+	byID := make(map[uint]models.HotelListItem, len(ids))
+	if len(ids) == 0 {
+		return byID, nil
+	}
+	var hotels []models.HotelListItem
+	if err := db.Table("hotels h").
+		Joins("LEFT JOIN hotel_ratings_summaries s ON s.hotel_id = h.id").
+		Select(hotelListColumns).
+		Where("h.id IN ?", ids).
+		Scan(&hotels).Error; err != nil {
+		return nil, err
+	}
+	if err := attachPlatforms(db, hotels); err != nil {
+		return nil, err
+	}
+	for _, h := range hotels {
+		byID[h.ID] = h
+	}
+	return byID, nil
+}
+
+// attachPlatforms sets the names of the platforms each hotel is listed on.
+func attachPlatforms(db *gorm.DB, hotels []models.HotelListItem) error {
+	if len(hotels) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(hotels))
+	for i, h := range hotels {
+		ids[i] = h.ID
+		hotels[i].Platforms = []string{}
+	}
+	var rows []struct {
+		HotelID  uint
+		Platform string
+	}
+	if err := db.Table("hotel_platform_listings l").
+		Select("DISTINCT l.hotel_id, p.name AS platform").
+		Joins("JOIN platforms p ON p.id = l.platform_id").
+		Where("l.hotel_id IN ?", ids).
+		Order("l.hotel_id, p.name").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+	byHotel := make(map[uint][]string)
+	for _, r := range rows {
+		byHotel[r.HotelID] = append(byHotel[r.HotelID], r.Platform)
+	}
+	for i := range hotels {
+		if p, ok := byHotel[hotels[i].ID]; ok {
+			hotels[i].Platforms = p
+		}
+	}
+	return nil
+}
+
+// escapeLike escapes the LIKE wildcards in s.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
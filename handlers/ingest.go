@@ -7,6 +7,9 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"review-system/internal/hotels"
+	"review-system/internal/ingestion"
+	"review-system/internal/langdetect"
 	"review-system/models"
 	"strings"
 	"sync"
@@ -83,23 +86,35 @@ func processJLLine(raw map[string]interface{}, db *gorm.DB) {
 	comment := raw["comment"].(map[string]interface{})
 	reviewerInfo := comment["reviewerInfo"].(map[string]interface{})
 
-	hotel := models.Hotel{ExternalID: hotelID, Name: hotelName}
-	db.FirstOrCreate(&hotel, models.Hotel{ExternalID: hotelID})
+	var platform models.Platform
+	db.Where(models.Platform{Name: platformName}).
+		Attrs(models.Platform{Name: platformName}.WithDefaultScale()).
+		FirstOrCreate(&platform)
 
-	platform := models.Platform{Name: platformName}
-	db.FirstOrCreate(&platform, platform)
+	listing, _, err := hotels.ResolveListing(db, hotels.ListingInput{
+		PlatformID: platform.ID,
+		ExternalID: hotelID,
+		Name:       hotelName,
+	})
+	if err != nil {
+		log.Printf("❌ %v", err)
+		return
+	}
 
-	reviewer := models.Reviewer{
-		CountryName:     getStr(reviewerInfo["countryName"]),
-		ReviewGroupName: getStr(reviewerInfo["reviewGroupName"]),
-		RoomTypeName:    getStr(reviewerInfo["roomTypeName"]),
+	dims, err := models.ResolveDimensions(db, platform.ID, listing.ID, models.ReviewerInfo{
+		CountryName:  getStr(reviewerInfo["countryName"]),
+		TravelerType: getStr(reviewerInfo["reviewGroupName"]),
+		RoomTypeName: getStr(reviewerInfo["roomTypeName"]),
+	})
+	if err != nil {
+		log.Printf("❌ %v", err)
+		return
 	}
-	db.FirstOrCreate(&reviewer, reviewer)
 
 	hotelReviewID := parseInt64(comment["hotelReviewId"])
 
 	var existing models.Review
-	err := db.Where("hotel_review_id = ?", hotelReviewID).First(&existing).Error
+	err = db.Where("hotel_review_id = ?", hotelReviewID).First(&existing).Error
 	if err == nil {
 		// Already exists
 		return
@@ -110,34 +125,56 @@ func processJLLine(raw map[string]interface{}, db *gorm.DB) {
 	}
 
 	review := models.Review{
-		HotelID:       hotel.ID,
-		PlatformID:    platform.ID,
-		ReviewerID:    reviewer.ID,
-		HotelReviewID: hotelReviewID,
-		Rating:        float32(comment["rating"].(float64)),
-		ReviewTitle:   getStr(comment["reviewTitle"]),
-		ReviewText:    getStr(comment["reviewComments"]),
-		ReviewDate:    parseTime(getStr(comment["reviewDate"])),
+		HotelID:          listing.HotelID,
+		PlatformID:       platform.ID,
+		ListingID:        listing.ID,
+		HotelReviewID:    hotelReviewID,
+		Rating:           float32(comment["rating"].(float64)),
+		ReviewTitle:      getStr(comment["reviewTitle"]),
+		ReviewText:       getStr(comment["reviewComments"]),
+		ReviewDate:       parseTime(getStr(comment["reviewDate"])),
+		ModerationStatus: models.ModerationVisible,
+
+		CountryID:      dims.CountryID,
+		TravelerTypeID: dims.TravelerTypeID,
+		RoomTypeID:     dims.RoomTypeID,
+	}
+	review.NormalizedRating = platform.Normalize(review.Rating)
+	if err := ingestion.RedactReview(&review); err != nil {
+		log.Printf("❌ Failed to redact review (hotelReviewId=%d): %v", hotelReviewID, err)
+		return
 	}
+	review.Language, _ = langdetect.Detect(review.ReviewTitle + "\n" + review.ReviewText)
+	review.ScoreSentiment()
+	review.SignReview()
 
 	if err := db.Create(&review).Error; err != nil {
 		log.Printf("❌ Failed to insert review (hotelReviewId=%d): %v", hotelReviewID, err)
 		return
 	}
+	if err := models.LinkDuplicates(db, &review); err != nil {
+		log.Printf("⚠️  Failed to link duplicates (hotelReviewId=%d): %v", hotelReviewID, err)
+	}
+	if err := models.ScoreSuspicion(db, &review); err != nil {
+		log.Printf("⚠️  Failed to score suspicion (hotelReviewId=%d): %v", hotelReviewID, err)
+	}
+	if err := models.SaveAspectMentions(db, review); err != nil {
+		log.Printf("⚠️  Failed to save aspect mentions (hotelReviewId=%d): %v", hotelReviewID, err)
+	}
 
 	var summary models.HotelRatingsSummary
-	if err := db.First(&summary, "hotel_id = ?", hotel.ID).Error; err != nil {
+	if err := db.First(&summary, "hotel_id = ?", listing.HotelID).Error; err != nil {
 		summary = models.HotelRatingsSummary{
-			HotelID:       hotel.ID,
+			HotelID:       listing.HotelID,
 			TotalReviews:  1,
-			TotalRating:   float64(review.Rating),
-			AverageRating: float64(review.Rating),
+			TotalRating:   float64(review.NormalizedRating),
+			AverageRating: float64(review.NormalizedRating),
 			LastUpdated:   time.Now(),
 		}
 		db.Create(&summary)
 	} else {
 		summary.TotalReviews++
-		summary.TotalRating += float64(review.Rating)
+		summary.TotalRating += float64(review.NormalizedRating)
 		summary.AverageRating = summary.TotalRating / float64(summary.TotalReviews)
 		summary.LastUpdated = time.Now()
 		db.Save(&summary)
@@ -166,9 +203,12 @@ func parseInt64(val interface{}) int64 {
 	}
 }
 
-func parseTime(val string) time.Time {
-	t, _ := time.Parse(time.RFC3339, val)
-	return t
+func parseTime(val string) *time.Time {
+	t, err := time.Parse(time.RFC3339, val)
+	if err != nil {
+		return nil
+	}
+	return &t
 }
 
 // --- Ingestion Marker ---
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"review-system/internal/moderation"
+	"review-system/models"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type ModerateReviewsRequest struct {
+	ReviewIDs []uint `json:"review_ids"`
+	Status    string `json:"status"` // visible, pending, hidden or removed
+	Reason    string `json:"reason"`
+}
+
+// ModerateReviews godoc
+// @Summary Change the moderation status of reviews
+// @Description Moves up to 500 reviews to visible, pending, hidden or removed. A reason is required
+// @Description for every status but visible. Each change is recorded with the token's actor, and
+// @Description the rating summaries of hotels whose visible reviews changed are recomputed.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param body body ModerateReviewsRequest true "Reviews, status and reason"
+// @Success 200 {object} moderation.Result
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/reviews/moderation [post]
+func ModerateReviews(c echo.Context) error {
+	var req ModerateReviewsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid request body"})
+	}
+	result, err := moderation.Apply(models.GetDB(), req.ReviewIDs, req.Status, req.Reason, Actor(c))
+	if errors.Is(err, moderation.ErrInvalid) {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	} else if err != nil {
+		c.Logger().Errorf("moderation: %v", err)
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Moderation failed"})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetModerationActions godoc
+// @Summary List the moderation audit trail
+// @Description Returns moderation status changes, newest first.
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Param review_id query int false "Only this review"
+// @Param actor query string false "Only changes by this actor"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Actions per page" default(20)
+// @Success 200 {object} models.ModerationActionPage
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/moderation-actions [get]
+func GetModerationActions(c echo.Context) error {
+	page, limit := 1, 20
+	if p, err := strconv.Atoi(c.QueryParam("page")); err == nil && p > 0 {
+		page = p
+	}
+	if l, err := strconv.Atoi(c.QueryParam("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+
+	query := models.GetDB().Model(&models.ModerationAction{})
+	if v := c.QueryParam("review_id"); v != "" {
+		reviewID, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid review_id"})
+		}
+		query = query.Where("review_id = ?", reviewID)
+	}
+	if actor := c.QueryParam("actor"); actor != "" {
+		query = query.Where("actor = ?", actor)
+	}
+
+	result := models.ModerationActionPage{Page: page, Limit: limit, Actions: []models.ModerationAction{}}
+	if err := query.Session(&gorm.Session{}).Count(&result.Total).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to count moderation actions"})
+	}
+	if err := query.Order("id DESC").Limit(limit).Offset((page - 1) * limit).
+		Find(&result.Actions).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch moderation actions"})
+	}
+	return c.JSON(http.StatusOK, result)
+}
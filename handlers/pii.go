@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"review-system/internal/ingestion"
+	"review-system/models"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// GetReviewOriginal godoc
+// @Summary Get a review's original, unredacted text
+// @Description Returns the title and text as the provider sent them, before personal data was
+// @Description redacted. Requires a token with the pii_reader role. Every read is logged.
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Param id path int true "Review ID"
+// @Success 200 {object} models.ReviewOriginal
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/reviews/{id}/original [get]
+func GetReviewOriginal(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid review id"})
+	}
+
+	var review models.Review
+	if err := models.GetDB().First(&review, id).Error; err == gorm.ErrRecordNotFound {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": "Review not found"})
+	} else if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch review"})
+	}
+
+	title, text, err := ingestion.OriginalText(review)
+	if errors.Is(err, ingestion.ErrOriginalNotKept) {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": "Original text was not kept"})
+	} else if err != nil {
+		log.Printf("❌ Failed to read original text of review %d: %v", review.ID, err)
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to read original text"})
+	}
+	log.Printf("🔓 Original text of review %d read by %s from %s", review.ID, Actor(c), c.RealIP())
+
+	original := models.ReviewOriginal{
+		ID:            review.ID,
+		HotelReviewID: review.HotelReviewID,
+		ReviewTitle:   title,
+		ReviewText:    text,
+		PIIKinds:      []string{},
+	}
+	if review.PIIKinds != nil && *review.PIIKinds != "" {
+		original.PIIKinds = strings.Split(*review.PIIKinds, ",")
+	}
+	return c.JSON(http.StatusOK, original)
+}
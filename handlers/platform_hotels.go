@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"review-system/models"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// maxResolveBatch is the most external IDs one resolve call may look up.
+const maxResolveBatch = 500
+
+var (
+	errInvalidExternalID = errors.New("invalid external hotel id")
+	errUnknownPlatform   = errors.New("platform not found")
+	errUnknownHotel      = errors.New("hotel not found")
+)
+
+type ResolveHotelsRequest struct {
+	ExternalIDs []int `json:"external_ids"`
+}
+
+// GetPlatformHotel godoc
+// @Summary Get a hotel by the provider's hotel ID
+// @Description Resolves a platform's own hotel ID, e.g. Agoda 10984, to our hotel and returns it
+// @Description with its rating summary.
+// @Tags hotels
+// @Produce json
+// @Param platform path string true "Platform name, case-insensitive"
+// @Param externalId path int true "The platform's hotel ID"
+// @Success 200 {object} models.HotelListItem
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /platforms/{platform}/hotels/{externalId} [get]
+func GetPlatformHotel(c echo.Context) error {
+	db := models.GetDB()
+	hotelID, err := resolveExternalHotel(db, c.Param("platform"), c.Param("externalId"))
+	if err != nil {
+		return platformHotelError(c, err)
+	}
+	hotels, err := loadHotelListItems(db, []uint{hotelID})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch hotel"})
+	}
+	return c.JSON(http.StatusOK, hotels[hotelID])
+}
+
+// GetPlatformHotelReviews godoc
+// @Summary Get a hotel's reviews by the provider's hotel ID
+// @Description Resolves a platform's own hotel ID to our hotel and returns the same response as
+// @Description GET /hotels/{hotel_id}/reviews, reviews from every platform included, and takes the
+// @Description same query parameters.
+// @Tags reviews
+// @Produce json
+// @Param platform path string true "Platform name, case-insensitive"
+// @Param externalId path int true "The platform's hotel ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Reviews per page" default(20)
+// @Param lang query string false "Only reviews in this ISO 639-1 language, or und for undetermined"
+// @Param dedupe query bool false "Count each cluster of near-duplicate reviews once in the summary, aspects and language breakdown"
+// @Param exclude_suspicious query bool false "Leave out reviews scored at or above the suspicion threshold"
+// @Success 200 {object} models.ReviewResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /platforms/{platform}/hotels/{externalId}/reviews [get]
+func GetPlatformHotelReviews(c echo.Context) error {
+	hotelID, err := resolveExternalHotel(models.GetDB(), c.Param("platform"), c.Param("externalId"))
+	if err != nil {
+		return platformHotelError(c, err)
+	}
+	c.SetParamNames("id")
+	c.SetParamValues(strconv.FormatUint(uint64(hotelID), 10))
+	return GetHotelReviews(c)
+}
+
+// ResolvePlatformHotels godoc
+// @Summary Resolve provider hotel IDs in bulk
+// @Description Maps up to 500 of a platform's hotel IDs to our hotels and their rating summaries.
+// @Description IDs we have no listing for resolve to a null hotel.
+// @Tags hotels
+// @Accept json
+// @Produce json
+// @Param platform path string true "Platform name, case-insensitive"
+// @Param body body ResolveHotelsRequest true "The platform's hotel IDs"
+// @Success 200 {array} models.ResolvedHotel
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /platforms/{platform}/hotels/resolve [post]
+func ResolvePlatformHotels(c echo.Context) error {
+	var req ResolveHotelsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid request body"})
+	}
+	if len(req.ExternalIDs) == 0 || len(req.ExternalIDs) > maxResolveBatch {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "external_ids must hold between 1 and 500 IDs"})
+	}
+
+	db := models.GetDB()
+	platform, err := platformByName(db, c.Param("platform"))
+	if err != nil {
+		return platformHotelError(c, err)
+	}
+
+	var listings []models.HotelPlatformListing
+	if err := db.Where("platform_id = ? AND external_id IN ?", platform.ID, req.ExternalIDs).
+		Find(&listings).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to resolve hotels"})
+	}
+	hotelOf := make(map[int]uint, len(listings))
+	hotelIDs := make([]uint, 0, len(listings))
+	for _, l := range listings {
+		hotelOf[l.ExternalID] = l.HotelID
+		hotelIDs = append(hotelIDs, l.HotelID)
+	}
+	hotels, err := loadHotelListItems(db, hotelIDs)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch hotels"})
+	}
+
+	resolved := make([]models.ResolvedHotel, len(req.ExternalIDs))
+	for i, externalID := range req.ExternalIDs {
+		resolved[i] = models.ResolvedHotel{Platform: platform.Name, ExternalID: externalID}
+		if hotelID, ok := hotelOf[externalID]; ok {
+			hotel := hotels[hotelID]
+			resolved[i].HotelID, resolved[i].Hotel = &hotelID, &hotel
+		}
+	}
+	return c.JSON(http.StatusOK, resolved)
+}
+
+// resolveExternalHotel maps a platform name and the platform's hotel ID to
+// our hotel ID.
+func resolveExternalHotel(db *gorm.DB, platformName, externalID string) (uint, error) {
+	id, err := strconv.Atoi(externalID)
+	if err != nil {
+		return 0, errInvalidExternalID
+	}
+	platform, err := platformByName(db, platformName)
+	if err != nil {
+		return 0, err
+	}
+	var listing models.HotelPlatformListing
+	err = db.Where("platform_id = ? AND external_id = ?", platform.ID, id).First(&listing).Error
+	if err == gorm.ErrRecordNotFound {
+		return 0, errUnknownHotel
+	}
+	return listing.HotelID, err
+}
+
+func platformByName(db *gorm.DB, name string) (models.Platform, error) {
+	var platform models.Platform
+	err := db.Where("LOWER(name) = LOWER(?)", name).First(&platform).Error
+	if err == gorm.ErrRecordNotFound {
+		return platform, errUnknownPlatform
+	}
+	return platform, err
+}
+
+func platformHotelError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, errInvalidExternalID):
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid external hotel id"})
+	case errors.Is(err, errUnknownPlatform):
+		return c.JSON(http.StatusNotFound, echo.Map{"error": "Platform not found"})
+	case errors.Is(err, errUnknownHotel):
+		return c.JSON(http.StatusNotFound, echo.Map{"error": "Hotel not found"})
+	}
+	c.Logger().Errorf("platform hotel lookup: %v", err)
+	return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to resolve hotel"})
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"review-system/models"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// GetRatingsBreakdown godoc
+// @Summary Get a hotel's rating breakdown by aspect
+// @Description Returns the overall average and the average of each aspect (cleanliness, location,
+// @Description staff, value, facilities), all on the normalized 0–10 scale. Each aspect has its
+// @Description own count, since not every review scores every aspect.
+// @Tags reviews
+// @Produce json
+// @Param hotel_id path int true "Hotel ID"
+// @Param dedupe query bool false "Count each cluster of near-duplicate reviews once"
+// @Success 200 {object} models.RatingsBreakdown
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hotels/{hotel_id}/ratings/breakdown [get]
+func GetRatingsBreakdown(c echo.Context) error {
+	hotelID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid hotel id"})
+	}
+	dedupe, err := dedupeFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	db := models.GetDB()
+
+	var summary models.HotelRatingsSummary
+	if err := db.First(&summary, "hotel_id = ?", hotelID).Error; err == gorm.ErrRecordNotFound {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": "Hotel not found"})
+	} else if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch summary"})
+	}
+
+	// The summary counts every duplicate, so deduplicated totals are
+	// computed from the reviews.
+	if dedupe != "" {
+		if err := db.Raw(`
+            SELECT COUNT(*) AS total_reviews, COALESCE(ROUND(AVG(r.normalized_rating)::numeric, 2), 0) AS average_rating
+            FROM reviews r
+            WHERE r.hotel_id = ? AND `+models.VisibleOnly+` `+dedupe+`
+        `, hotelID).Scan(&summary).Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch summary"})
+		}
+	}
+
+	aspects, err := hotelAspects(db, hotelID, dedupe)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch aspect ratings"})
+	}
+
+	return c.JSON(http.StatusOK, models.RatingsBreakdown{
+		HotelID:       summary.HotelID,
+		TotalReviews:  summary.TotalReviews,
+		AverageRating: summary.AverageRating,
+		Aspects:       aspects,
+	})
+}
+
+// GetRatingTrend godoc
+// @Summary Get a hotel's daily rating trend
+// @Description Returns the number of reviews and their average normalized rating per day. Days are
+// @Description calendar days at the hotel, in its timezone when one is set. Reviews without a
+// @Description usable date are left out.
+// @Tags reviews
+// @Produce json
+// @Param hotel_id path int true "Hotel ID"
+// @Param from query string false "First day, YYYY-MM-DD" default(29 days before to)
+// @Param to query string false "Last day, YYYY-MM-DD" default(today at the hotel)
+// @Param dedupe query bool false "Count each cluster of near-duplicate reviews once"
+// @Success 200 {object} models.RatingTrend
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hotels/{hotel_id}/ratings/trend [get]
+func GetRatingTrend(c echo.Context) error {
+	hotelID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid hotel id"})
+	}
+	db := models.GetDB()
+
+	var hotel models.Hotel
+	if err := db.First(&hotel, hotelID).Error; err == gorm.ErrRecordNotFound {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": "Hotel not found"})
+	} else if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch hotel"})
+	}
+
+	from, to, err := localDateWindow(c, hotel, 30)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	dedupe, err := dedupeFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+
+	var rows []struct {
+		Day           time.Time
+		ReviewCount   int
+		AverageRating float64
+	}
+	if err := db.Raw(`
+        SELECT r.review_local_date AS day, COUNT(*) AS review_count,
+               ROUND(AVG(r.normalized_rating)::numeric, 2) AS average_rating
+        FROM reviews r
+        WHERE r.hotel_id = ? AND r.review_local_date BETWEEN ? AND ? AND `+models.VisibleOnly+` `+dedupe+`
+        GROUP BY r.review_local_date
+        ORDER BY r.review_local_date
+    `, hotelID, from, to).Scan(&rows).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch rating trend"})
+	}
+
+	trend := models.RatingTrend{HotelID: hotel.ID, Timezone: hotel.Timezone, Days: []models.DailyRating{}}
+	for _, r := range rows {
+		trend.Days = append(trend.Days, models.DailyRating{
+			Day:           r.Day.Format("2006-01-02"),
+			ReviewCount:   r.ReviewCount,
+			AverageRating: r.AverageRating,
+		})
+	}
+	return c.JSON(http.StatusOK, trend)
+}
+
+// localDateWindow reads the from and to query parameters as calendar days at
+// the hotel. to defaults to today there and from to the days-long window
+// ending at to. A from later than to is an error.
+func localDateWindow(c echo.Context, hotel models.Hotel, days int) (from, to time.Time, err error) {
+	now := time.Now().UTC()
+	if loc := hotel.Location(); loc != nil {
+		now = now.In(loc)
+	}
+	to = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if v := c.QueryParam("to"); v != "" {
+		if to, err = time.Parse("2006-01-02", v); err != nil {
+			return from, to, errors.New("Invalid to date, want YYYY-MM-DD")
+		}
+	}
+	from = to.AddDate(0, 0, 1-days)
+	if v := c.QueryParam("from"); v != "" {
+		if from, err = time.Parse("2006-01-02", v); err != nil {
+			return from, to, errors.New("Invalid from date, want YYYY-MM-DD")
+		}
+	}
+	if from.After(to) {
+		return from, to, errors.New("from must not be after to")
+	}
+	return from, to, nil
+}
+
+// dedupeFilter reads the dedupe query parameter and returns the condition
+// that counts each duplicate cluster once, or "" when it is off.
+func dedupeFilter(c echo.Context) (string, error) {
+	v := c.QueryParam("dedupe")
+	if v == "" {
+		return "", nil
+	}
+	on, err := strconv.ParseBool(v)
+	if err != nil {
+		return "", errors.New("dedupe must be true or false")
+	}
+	if !on {
+		return "", nil
+	}
+	return "AND " + models.CountClusterOnce, nil
+}
+
+// hotelAspects loads a hotel's per-aspect averages in display order. With a
+// dedupeFilter condition they are computed from the sub-ratings of the
+// reviews it keeps instead of read from the aspect summaries.
+func hotelAspects(db *gorm.DB, hotelID interface{}, dedupe string) ([]models.AspectRating, error) {
+	var rows []models.HotelAspectSummary
+	if dedupe == "" {
+		if err := db.Where("hotel_id = ? AND total_ratings > 0", hotelID).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+	} else if err := db.Raw(`
+        SELECT s.aspect, COUNT(*) AS total_ratings, AVG(s.normalized_rating) AS average_rating
+        FROM review_sub_ratings s
+        JOIN reviews r ON r.id = s.review_id
+        WHERE r.hotel_id = ? AND `+models.VisibleOnly+` `+dedupe+`
+        GROUP BY s.aspect
+    `, hotelID).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	byAspect := make(map[string]models.HotelAspectSummary, len(rows))
+	for _, r := range rows {
+		byAspect[r.Aspect] = r
+	}
+
+	aspects := []models.AspectRating{}
+	for _, name := range models.Aspects {
+		if r, ok := byAspect[name]; ok {
+			aspects = append(aspects, models.AspectRating{
+				Aspect:        name,
+				TotalRatings:  r.TotalRatings,
+				AverageRating: math.Round(r.AverageRating*100) / 100,
+			})
+		}
+	}
+	return aspects, nil
+}
+
+// attachSubRatings adds a "sub_ratings" list to each review row, keyed by
+// the row's "id".
+func attachSubRatings(db *gorm.DB, reviews []map[string]interface{}) error {
+	ids := make([]interface{}, 0, len(reviews))
+	for _, r := range reviews {
+		ids = append(ids, r["id"])
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var subs []models.ReviewSubRating
+	if err := db.Where("review_id IN ?", ids).Order("id").Find(&subs).Error; err != nil {
+		return err
+	}
+	byReview := make(map[uint][]models.SubRatingDetail)
+	for _, s := range subs {
+		byReview[s.ReviewID] = append(byReview[s.ReviewID], models.SubRatingDetail{
+			Aspect:           s.Aspect,
+			Rating:           s.Rating,
+			NormalizedRating: s.NormalizedRating,
+		})
+	}
+
+	for _, r := range reviews {
+		id, _ := strconv.ParseUint(getStr(r["id"]), 10, 64)
+		details := byReview[uint(id)]
+		if details == nil {
+			details = []models.SubRatingDetail{}
+		}
+		r["sub_ratings"] = details
+	}
+	return nil
+}
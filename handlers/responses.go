@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"review-system/internal/ingestion"
+	"review-system/models"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReviewReplyRequest struct {
+	Author string `json:"author"`
+	Text   string `json:"text"`
+}
+
+// SaveReviewResponse godoc
+// @Summary Write a local draft reply to a review
+// @Description Stores the hotel's draft reply to a review, replacing any earlier draft. The reply the
+// @Description platform publishes is ingested separately and is not changed. Personal data is
+// @Description redacted from the text as it is from reviews. Drafts are not shown on
+// @Description public endpoints. The author defaults to the token's actor. Requires a token with the
+// @Description responder role.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param id path int true "Hotel ID"
+// @Param reviewId path int true "Review ID"
+// @Param body body ReviewReplyRequest true "Reply"
+// @Success 200 {object} models.ResponseDetail
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hotels/{id}/reviews/{reviewId}/response [post]
+func SaveReviewResponse(c echo.Context) error {
+	hotelID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid hotel id"})
+	}
+	reviewID, err := strconv.ParseUint(c.Param("reviewId"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid review id"})
+	}
+	var req ReviewReplyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid request body"})
+	}
+	req.Text = strings.TrimSpace(req.Text)
+	if req.Text == "" {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "text is required"})
+	}
+
+	req.Author = strings.TrimSpace(req.Author)
+	if req.Author == "" {
+		req.Author = Actor(c)
+	}
+
+	db := models.GetDB()
+	var review models.Review
+	if err := db.Where("id = ? AND hotel_id = ?", reviewID, hotelID).First(&review).Error; err == gorm.ErrRecordNotFound {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": "Review not found"})
+	} else if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch review"})
+	}
+
+	now := time.Now()
+	reply := models.ManagementResponse{
+		ReviewID:     review.ID,
+		Source:       models.ResponseSourceLocal,
+		Author:       req.Author,
+		Text:         req.Text,
+		ResponseDate: &now,
+	}
+	ingestion.RedactResponse(&reply)
+	if err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "review_id"}, {Name: "source"}},
+		DoUpdates: clause.AssignmentColumns([]string{"author", "text", "pii_kinds", "response_date", "updated_at"}),
+	}).Create(&reply).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to save response"})
+	}
+
+	return c.JSON(http.StatusOK, responseDetail(reply))
+}
+
+// GetReviewResponses godoc
+// @Summary List a review's replies, including the local draft
+// @Description Returns the reply the platform published and the local draft, whichever exist, so
+// @Description a draft can be reviewed next to what guests see. Requires a token with the responder
+// @Description role.
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Param id path int true "Hotel ID"
+// @Param reviewId path int true "Review ID"
+// @Success 200 {array} models.ResponseDetail
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hotels/{id}/reviews/{reviewId}/response [get]
+func GetReviewResponses(c echo.Context) error {
+	hotelID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid hotel id"})
+	}
+	reviewID, err := strconv.ParseUint(c.Param("reviewId"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid review id"})
+	}
+
+	db := models.GetDB()
+	var review models.Review
+	if err := db.Where("id = ? AND hotel_id = ?", reviewID, hotelID).First(&review).Error; err == gorm.ErrRecordNotFound {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": "Review not found"})
+	} else if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch review"})
+	}
+
+	var replies []models.ManagementResponse
+	if err := db.Where("review_id = ?", review.ID).Order("source").Find(&replies).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch responses"})
+	}
+	out := make([]models.ResponseDetail, len(replies))
+	for i, r := range replies {
+		out[i] = responseDetail(r)
+	}
+	return c.JSON(http.StatusOK, out)
+}
+
+func responseDetail(r models.ManagementResponse) models.ResponseDetail {
+	return models.ResponseDetail{
+		Source:       r.Source,
+		Author:       r.Author,
+		Text:         r.Text,
+		ResponseDate: r.ResponseDate,
+	}
+}
+
+// attachResponses adds a "response" to each review row, keyed by the row's
+// "id": the reply the platform published, or null. Local drafts are never
+// shown publicly.
+func attachResponses(db *gorm.DB, reviews []map[string]interface{}) error {
+	ids := make([]interface{}, 0, len(reviews))
+	for _, r := range reviews {
+		ids = append(ids, r["id"])
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var replies []models.ManagementResponse
+	if err := db.Where("review_id IN ? AND source = ?", ids, models.ResponseSourcePlatform).Find(&replies).Error; err != nil {
+		return err
+	}
+	byReview := make(map[uint]models.ManagementResponse, len(replies))
+	for _, r := range replies {
+		byReview[r.ReviewID] = r
+	}
+
+	for _, r := range reviews {
+		id, _ := strconv.ParseUint(getStr(r["id"]), 10, 64)
+		if reply, ok := byReview[uint(id)]; ok {
+			r["response"] = responseDetail(reply)
+		} else {
+			r["response"] = nil
+		}
+	}
+	return nil
+}
+
+// hotelResponseMetrics reports how many of a hotel's reviews have a reply
+// published on the platform and the median time from review to reply. Local
+// drafts are not counted until the platform shows them.
+func hotelResponseMetrics(db *gorm.DB, hotelID interface{}) (models.ResponseMetrics, error) {
+	var metrics models.ResponseMetrics
+	if err := db.Raw(`
+        SELECT COUNT(*) AS total_reviews,
+               COUNT(rr.review_id) AS responded_reviews,
+               PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM rr.response_date - r.review_date) / 3600)
+                   FILTER (WHERE rr.response_date >= r.review_date) AS median_response_hours
+        FROM reviews r
+        LEFT JOIN review_responses rr ON rr.review_id = r.id AND rr.source = ?
+        WHERE r.hotel_id = ? AND `+models.VisibleOnly+`
+    `, models.ResponseSourcePlatform, hotelID).Scan(&metrics).Error; err != nil {
+		return metrics, err
+	}
+	if metrics.TotalReviews > 0 {
+		metrics.ResponseRate = math.Round(float64(metrics.RespondedReviews)/float64(metrics.TotalReviews)*1000) / 1000
+	}
+	if metrics.MedianResponseHours != nil {
+		rounded := math.Round(*metrics.MedianResponseHours*10) / 10
+		metrics.MedianResponseHours = &rounded
+	}
+	return metrics, nil
+}
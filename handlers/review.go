@@ -1,30 +1,62 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"review-system/models"
 
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 )
 
+// undeterminedLanguage stands for reviews whose language was not detected,
+// as in ISO 639-2.
+const undeterminedLanguage = "und"
+
 // GetHotelReviews godoc
 // @Summary Get hotel reviews and overall rating
-// @Description Returns average rating and paginated reviews for a hotel
+// @Description Returns average rating and paginated reviews for a hotel. The average is on the
+// @Description normalized 0–10 scale; each review carries both its raw and normalized rating and
+// @Description any per-aspect sub-ratings the platform sent. Reviews include the hotel's reply, and
+// @Description the response carries the hotel's response rate and median response time. Filters
+// @Description narrow the reviews listed; the summary covers every review unless filtered_summary
+// @Description is set. Dates are calendar days at the hotel. Pages can be walked with page or,
+// @Description faster and stable while reviews arrive, by passing next_cursor back as cursor.
 // @Tags reviews
 // @Produce json
 // @Param hotel_id path int true "Hotel ID"
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Reviews per page" default(20)
+// @Param cursor query string false "next_cursor of the previous page; page is ignored with it"
+// @Param include_total query bool false "Count the reviews matching the filters"
+// @Param sort query string false "newest, oldest, highest or lowest" default(newest)
+// @Param lang query string false "Only reviews in this ISO 639-1 language, or und for undetermined"
+// @Param platform query string false "Only reviews from this platform"
+// @Param country query string false "Only reviewers from this country, by ISO 3166-1 alpha-2 code or name"
+// @Param review_group query string false "Only this traveler group, e.g. Couple"
+// @Param room_type query string false "Only this room type"
+// @Param min_rating query number false "Lowest normalized rating, 0 to 10"
+// @Param max_rating query number false "Highest normalized rating, 0 to 10"
+// @Param from query string false "First review day, YYYY-MM-DD"
+// @Param to query string false "Last review day, YYYY-MM-DD"
+// @Param days query int false "Only the last this many days up to today, instead of from and to"
+// @Param has_text query bool false "Only reviews with (true) or without (false) text"
+// @Param filtered_summary query bool false "Apply the filters to the summary too"
+// @Param dedupe query bool false "Count each cluster of near-duplicate reviews once in the summary, aspects and language breakdown"
+// @Param exclude_suspicious query bool false "Leave out reviews scored at or above the suspicion threshold"
 // @Success 200 {object} map[string]interface{}
 // @Success 200 {object} models.ReviewResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /hotels/{hotel_id}/reviews [get]
 func GetHotelReviews(c echo.Context) error {
-	hotelID := c.Param("id")
-	if hotelID == "" {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Missing hotel_id in URL"})
+	hotelID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid hotel id"})
 	}
 
 	// Defaults
@@ -43,40 +75,189 @@ func GetHotelReviews(c echo.Context) error {
 		}
 	}
 
-	offset := (page - 1) * limit
+	sort := c.QueryParam("sort")
+	if sort == "" {
+		sort = "newest"
+	}
+	order, ok := reviewSorts[sort]
+	if !ok {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "sort must be newest, oldest, highest or lowest"})
+	}
+
+	var cursor *reviewCursor
+	if token := c.QueryParam("cursor"); token != "" {
+		decoded, err := decodeReviewCursor(token)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+		}
+		if decoded.Sort != sort || decoded.HotelID != uint(hotelID) {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": "cursor belongs to another hotel or sort"})
+		}
+		cursor = &decoded
+	}
+	includeTotal := false
+	if v := c.QueryParam("include_total"); v != "" {
+		if includeTotal, err = strconv.ParseBool(v); err != nil {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": "include_total must be true or false"})
+		}
+	}
+
 	db := models.GetDB()
+	var hotel models.Hotel
+	if err := db.First(&hotel, hotelID).Error; err == gorm.ErrRecordNotFound {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": "Hotel not found"})
+	} else if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch hotel"})
+	}
+
+	filters, err := parseReviewFilters(c, db, hotel)
+	var invalid filterError
+	if errors.As(err, &invalid) {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": invalid.Error()})
+	} else if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to read filters"})
+	}
+	filteredSummary := false
+	if v := c.QueryParam("filtered_summary"); v != "" {
+		if filteredSummary, err = strconv.ParseBool(v); err != nil {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": "filtered_summary must be true or false"})
+		}
+	}
+
+	dedupe, err := dedupeFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	suspicion, suspicionArgs, err := suspicionFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+
+	// A cursor replaces the offset: the page starts after the review it marks.
+	offset := (page - 1) * limit
+	listFilter, listArgs := filters.SQL(), filters.args
+	if cursor != nil {
+		cond, args := cursor.condition()
+		listFilter += " AND " + cond
+		listArgs = append(append([]interface{}{}, listArgs...), args...)
+		offset = 0
+	}
 
 	// Fetch summary
+	summaryFilter, summaryArgs := "", []interface{}{}
+	if filteredSummary {
+		summaryFilter, summaryArgs = filters.SQL(), filters.args
+	}
 	var summary models.AggregatedHotelReview
 	if err := db.Raw(`
-        SELECT h.id as hotel_id, h.name as hotel_name, ROUND(AVG(r.rating)::numeric, 2) AS average_rating, COUNT(*) as review_count
+        SELECT h.id as hotel_id, h.name as hotel_name, ROUND(AVG(r.normalized_rating)::numeric, 2) AS average_rating, COUNT(*) as review_count
         FROM reviews r
         JOIN hotels h ON h.id = r.hotel_id
-        WHERE r.hotel_id = ?
+        WHERE r.hotel_id = ? AND `+models.VisibleOnly+` `+dedupe+` `+suspicion+` `+summaryFilter+`
         GROUP BY h.id
-    `, hotelID).Scan(&summary).Error; err != nil {
+    `, append(append([]interface{}{hotelID}, suspicionArgs...), summaryArgs...)...).Scan(&summary).Error; err != nil {
 		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch summary"})
 	}
+	if summary.HotelID == 0 {
+		// No review matched; the hotel is still named.
+		summary.HotelID, summary.HotelName = hotel.ID, hotel.Name
+	}
 
 	// Fetch reviews paginated
 	var reviews []map[string]interface{}
 	if err := db.Raw(`
-        SELECT r.rating, r.review_title, r.review_text, r.review_date, rv.country_name, rv.review_group_name, rv.room_type_name
+        SELECT r.id, r.rating, r.normalized_rating, p.scale_max AS rating_scale_max, p.name AS platform,
+               r.review_title, r.review_text, r.review_date, r.review_local_date, r.language,
+               r.sentiment_score, r.sentiment_label, r.duplicate_cluster_id,
+               r.suspicion_score, r.suspicion_reasons,
+               c.name AS country_name, c.iso_code AS country_code, tt.name AS review_group_name,
+               rt.name AS room_type_name, rp.display_name AS reviewer_name, r.stay_date, r.length_of_stay
         FROM reviews r
-        JOIN reviewers rv ON rv.id = r.reviewer_id
-        WHERE r.hotel_id = ?
-        ORDER BY r.review_date DESC
+        JOIN platforms p ON p.id = r.platform_id
+        LEFT JOIN countries c ON c.id = r.country_id
+        LEFT JOIN traveler_types tt ON tt.id = r.traveler_type_id
+        LEFT JOIN room_types rt ON rt.id = r.room_type_id
+        LEFT JOIN reviewer_profiles rp ON rp.id = r.reviewer_profile_id
+        WHERE r.hotel_id = ? AND `+models.VisibleOnly+` `+suspicion+` `+listFilter+`
+        ORDER BY `+order+`
         LIMIT ? OFFSET ?
-    `, hotelID, limit, offset).Scan(&reviews).Error; err != nil {
+    `, append(append(append([]interface{}{hotelID}, suspicionArgs...), listArgs...), limit+1, offset)...).Scan(&reviews).Error; err != nil {
 		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch reviews"})
 	}
+	// One review more than the page tells whether another page follows.
+	pagination := models.ReviewPagination{Page: page, Limit: limit, HasMore: len(reviews) > limit}
+	if pagination.HasMore {
+		reviews = reviews[:limit]
+		next := cursorAt(sort, uint(hotelID), reviews[limit-1]).encode()
+		pagination.NextCursor = &next
+	}
+	if cursor != nil {
+		pagination.Page = 0
+	}
+	if includeTotal {
+		total, err := countHotelReviews(db, uint(hotelID), suspicion+" "+filters.SQL(), append(append([]interface{}{}, suspicionArgs...), filters.args...))
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to count reviews"})
+		}
+		pagination.Total = &total
+	}
+	if err := attachSubRatings(db, reviews); err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch sub-ratings"})
+	}
+	if err := attachResponses(db, reviews); err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch responses"})
+	}
+
+	aspects, err := hotelAspects(db, hotelID, dedupe)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch aspect ratings"})
+	}
+
+	var languages []models.LanguageCount
+	if err := db.Raw(`
+        SELECT COALESCE(NULLIF(r.language, ''), ?) AS language, COUNT(*) AS review_count
+        FROM reviews r
+        WHERE r.hotel_id = ? AND `+models.VisibleOnly+` `+dedupe+` `+suspicion+`
+        GROUP BY 1
+        ORDER BY review_count DESC, language
+    `, append([]interface{}{undeterminedLanguage, hotelID}, suspicionArgs...)...).Scan(&languages).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch language breakdown"})
+	}
+
+	responses, err := hotelResponseMetrics(db, hotelID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch response metrics"})
+	}
 
 	return c.JSON(http.StatusOK, echo.Map{
-		"hotel": summary,
-		"pagination": echo.Map{
-			"page":  page,
-			"limit": limit,
-		},
-		"reviews": reviews,
+		"hotel":      summary,
+		"aspects":    aspects,
+		"languages":  languages,
+		"responses":  responses,
+		"pagination": pagination,
+		"reviews":    reviews,
 	})
 }
+
+// countHotelReviews counts a hotel's visible reviews matching filter, read
+// from the rating summary when nothing is filtered.
+func countHotelReviews(db *gorm.DB, hotelID uint, filter string, args []interface{}) (int64, error) {
+	if strings.TrimSpace(filter) == "" {
+		var summary models.HotelRatingsSummary
+		err := db.Select("total_reviews").First(&summary, "hotel_id = ?", hotelID).Error
+		if err == gorm.ErrRecordNotFound {
+			return 0, nil
+		}
+		return int64(summary.TotalReviews), err
+	}
+	var total int64
+	err := db.Raw(`
+        SELECT COUNT(*) FROM reviews r
+        WHERE r.hotel_id = ? AND `+models.VisibleOnly+` `+filter,
+		append([]interface{}{hotelID}, args...)...).Scan(&total).Error
+	return total, err
+}
+
+func isLanguageCode(s string) bool {
+	return len(s) == 2 && s[0] >= 'a' && s[0] <= 'z' && s[1] >= 'a' && s[1] <= 'z'
+}
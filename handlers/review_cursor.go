@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// reviewCursor marks the last review of a page in the order of a sort, so
+// the next page starts right after it however many reviews arrive in the
+// meantime. Clients get it as an opaque token.
+type reviewCursor struct {
+	Sort    string     `json:"s"`
+	HotelID uint       `json:"h"`
+	Rating  float64    `json:"r,omitempty"` // highest and lowest only
+	Date    *time.Time `json:"d,omitempty"` // nil for a review without a date
+	ID      uint       `json:"i"`
+}
+
+func (c reviewCursor) encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeReviewCursor(token string) (reviewCursor, error) {
+	var c reviewCursor
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || json.Unmarshal(b, &c) != nil || c.ID == 0 {
+		return c, filterError("Invalid cursor")
+	}
+	if _, ok := reviewSorts[c.Sort]; !ok {
+		return c, filterError("Invalid cursor")
+	}
+	return c, nil
+}
+
+// cursorAt returns the cursor of a review row listed in sort.
+func cursorAt(sort string, hotelID uint, row map[string]interface{}) reviewCursor {
+	c := reviewCursor{Sort: sort, HotelID: hotelID}
+	id, _ := strconv.ParseUint(getStr(row["id"]), 10, 64)
+	c.ID = uint(id)
+	if date, ok := row["review_date"].(time.Time); ok {
+		c.Date = &date
+	}
+	if sort == "highest" || sort == "lowest" {
+		c.Rating, _ = strconv.ParseFloat(getStr(row["normalized_rating"]), 64)
+	}
+	return c
+}
+
+// condition returns the condition on reviews aliased r that keeps the
+// reviews after the cursor in its sort, mirroring reviewSorts.
+func (c reviewCursor) condition() (string, []interface{}) {
+	switch c.Sort {
+	case "oldest":
+		return c.afterDate(">")
+	case "highest", "lowest":
+		op := "<"
+		if c.Sort == "lowest" {
+			op = ">"
+		}
+		cond, args := c.afterDate("<")
+		return "(r.normalized_rating " + op + " ? OR (r.normalized_rating = ? AND " + cond + "))",
+			append([]interface{}{c.Rating, c.Rating}, args...)
+	default:
+		return c.afterDate("<")
+	}
+}
+
+// afterDate keeps the reviews after the cursor by review_date then id, both
+// descending for "<" and ascending for ">", undated reviews last. Dated
+// reviews are compared as a row so Postgres can seek the sort index to the
+// cursor rather than filter the reviews before it.
+func (c reviewCursor) afterDate(op string) (string, []interface{}) {
+	if c.Date == nil {
+		return "(r.review_date IS NULL AND r.id " + op + " ?)", []interface{}{c.ID}
+	}
+	return "((r.review_date, r.id) " + op + " (?, ?) OR r.review_date IS NULL)", []interface{}{*c.Date, c.ID}
+}
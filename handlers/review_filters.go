@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"review-system/models"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// maxFilterDays is the longest "last N days" window a review filter takes.
+const maxFilterDays = 3650
+
+// reviewSorts maps the sort parameter of the reviews endpoint to its ORDER
+// BY on reviews aliased r. r.id breaks ties so pages are stable.
+var reviewSorts = map[string]string{
+	"newest":  "r.review_date DESC NULLS LAST, r.id DESC",
+	"oldest":  "r.review_date ASC NULLS LAST, r.id ASC",
+	"highest": "r.normalized_rating DESC, r.review_date DESC NULLS LAST, r.id DESC",
+	"lowest":  "r.normalized_rating ASC, r.review_date DESC NULLS LAST, r.id DESC",
+}
+
+// filterError is a filter query parameter that failed validation.
+type filterError string
+
+func (e filterError) Error() string { return string(e) }
+
+// reviewFilters holds the filter query parameters of a hotel's reviews as
+// conditions on reviews aliased r.
+type reviewFilters struct {
+	conds []string
+	args  []interface{}
+}
+
+func (f *reviewFilters) add(cond string, args ...interface{}) {
+	f.conds = append(f.conds, cond)
+	f.args = append(f.args, args...)
+}
+
+// SQL returns the conditions, each prefixed with AND, to append to a WHERE
+// clause.
+func (f reviewFilters) SQL() string {
+	if len(f.conds) == 0 {
+		return ""
+	}
+	return "AND " + strings.Join(f.conds, " AND ")
+}
+
+// parseReviewFilters validates the filter query parameters of a hotel's
+// reviews. Dates are calendar days at the hotel, like the rating trend.
+func parseReviewFilters(c echo.Context, db *gorm.DB, hotel models.Hotel) (reviewFilters, error) {
+	var f reviewFilters
+
+	// "und" selects reviews whose language could not be detected
+	if lang := strings.ToLower(c.QueryParam("lang")); lang != "" {
+		if lang != undeterminedLanguage && !isLanguageCode(lang) {
+			return f, filterError("lang must be a two-letter ISO 639-1 code or und")
+		}
+		// Reviews stored before languages were detected and not recognized
+		// then keep a NULL language; newer ones hold "".
+		if lang == undeterminedLanguage {
+			f.add("COALESCE(r.language, '') = ''")
+		} else {
+			f.add("r.language = ?", lang)
+		}
+	}
+
+	if name := c.QueryParam("platform"); name != "" {
+		platform, err := platformByName(db, name)
+		if errors.Is(err, errUnknownPlatform) {
+			return f, filterError("Unknown platform")
+		} else if err != nil {
+			return f, err
+		}
+		f.add("r.platform_id = ?", platform.ID)
+	}
+	// Country by ISO 3166-1 alpha-2 code or name, traveler group and room
+	// type by name, all ignoring case.
+	if v := c.QueryParam("country"); v != "" {
+		f.add("r.country_id IN (SELECT id FROM countries WHERE iso_code = UPPER(?) OR LOWER(name) = LOWER(?))", v, v)
+	}
+	if v := c.QueryParam("review_group"); v != "" {
+		f.add("r.traveler_type_id IN (SELECT id FROM traveler_types WHERE LOWER(name) = LOWER(?))", v)
+	}
+	if v := c.QueryParam("room_type"); v != "" {
+		f.add("r.room_type_id IN (SELECT id FROM room_types WHERE LOWER(name) = LOWER(?))", v)
+	}
+
+	minRating, maxRating := -1.0, -1.0
+	for _, b := range []struct {
+		param string
+		bound *float64
+	}{{"min_rating", &minRating}, {"max_rating", &maxRating}} {
+		v := c.QueryParam(b.param)
+		if v == "" {
+			continue
+		}
+		rating, err := strconv.ParseFloat(v, 64)
+		if err != nil || rating < 0 || rating > models.NormalizedScaleMax {
+			return f, filterError(b.param + " must be between 0 and 10")
+		}
+		*b.bound = rating
+	}
+	if minRating >= 0 && maxRating >= 0 && minRating > maxRating {
+		return f, filterError("min_rating must not be above max_rating")
+	}
+	if minRating >= 0 {
+		f.add("r.normalized_rating >= ?", minRating)
+	}
+	if maxRating >= 0 {
+		f.add("r.normalized_rating <= ?", maxRating)
+	}
+
+	from, to, err := reviewDateRange(c, hotel)
+	if err != nil {
+		return f, err
+	}
+	if from != nil {
+		f.add("r.review_local_date >= ?", *from)
+	}
+	if to != nil {
+		f.add("r.review_local_date <= ?", *to)
+	}
+
+	if v := c.QueryParam("has_text"); v != "" {
+		hasText, err := strconv.ParseBool(v)
+		if err != nil {
+			return f, filterError("has_text must be true or false")
+		}
+		if hasText {
+			f.add("TRIM(r.review_text) <> ''")
+		} else {
+			f.add("TRIM(r.review_text) = ''")
+		}
+	}
+	return f, nil
+}
+
+// reviewDateRange reads from and to, or days for the last days days up to
+// today at the hotel. Either bound may be nil.
+func reviewDateRange(c echo.Context, hotel models.Hotel) (from, to *time.Time, err error) {
+	parse := func(name string) (*time.Time, error) {
+		v := c.QueryParam(name)
+		if v == "" {
+			return nil, nil
+		}
+		day, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return nil, filterError("Invalid " + name + " date, want YYYY-MM-DD")
+		}
+		return &day, nil
+	}
+	if from, err = parse("from"); err != nil {
+		return nil, nil, err
+	}
+	if to, err = parse("to"); err != nil {
+		return nil, nil, err
+	}
+
+	if v := c.QueryParam("days"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 1 || days > maxFilterDays {
+			return nil, nil, filterError("days must be between 1 and 3650")
+		}
+		if from != nil || to != nil {
+			return nil, nil, filterError("days cannot be combined with from or to")
+		}
+		now := time.Now().UTC()
+		if loc := hotel.Location(); loc != nil {
+			now = now.In(loc)
+		}
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		start := today.AddDate(0, 0, 1-days)
+		return &start, &today, nil
+	}
+	if from != nil && to != nil && from.After(*to) {
+		return nil, nil, filterError("from must not be after to")
+	}
+	return from, to, nil
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"errors"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"review-system/models"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// maxSearchQuery is the longest search query accepted, in bytes.
+const maxSearchQuery = 200
+
+// Postgres marks matches in snippets with these control characters, which
+// never occur in review text, so the snippet can be HTML-escaped before
+// they become <mark> tags.
+const (
+	matchStart = "\x01"
+	matchStop  = "\x02"
+)
+
+var (
+	snippetOptions = "StartSel=" + matchStart + ", StopSel=" + matchStop +
+		`, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=" … "`
+	titleOptions = "StartSel=" + matchStart + ", StopSel=" + matchStop + ", HighlightAll=true"
+	highlighter  = strings.NewReplacer(matchStart, "<mark>", matchStop, "</mark>")
+)
+
+// SearchReviews godoc
+// @Summary Search review titles and text
+// @Description Full-text search across every hotel's visible reviews, best matches first. q takes
+// @Description web search syntax: "quoted phrases", OR and -excluded words. Words are stemmed in
+// @Description the review's language, so "shuttles" finds "shuttle"; lang searches one language
+// @Description only. Snippets and titles mark matches with <mark> and are otherwise HTML-escaped.
+// @Tags reviews
+// @Produce json
+// @Param q query string true "Search query"
+// @Param hotel_id query int false "Only this hotel"
+// @Param platform query string false "Only this platform"
+// @Param lang query string false "Only reviews in this ISO 639-1 language"
+// @Param from query string false "First review day, YYYY-MM-DD"
+// @Param to query string false "Last review day, YYYY-MM-DD"
+// @Param days query int false "Only the last this many days, instead of from and to"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Results per page" default(20)
+// @Success 200 {object} models.ReviewSearchResults
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /reviews/search [get]
+func SearchReviews(c echo.Context) error {
+	q := strings.TrimSpace(c.QueryParam("q"))
+	if q == "" {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "q is required"})
+	}
+	if len(q) > maxSearchQuery {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "q must be at most 200 characters"})
+	}
+	page, limit := 1, 20
+	if p, err := strconv.Atoi(c.QueryParam("page")); err == nil && p > 0 {
+		page = p
+	}
+	if l, err := strconv.Atoi(c.QueryParam("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+
+	// Each review is matched with the query parsed in its own language, or
+	// only lang's when it is given.
+	configs := models.SearchConfigs()
+	db := models.GetDB()
+	query := db.Table("reviews r").
+		Joins("JOIN hotels h ON h.id = r.hotel_id").
+		Joins("JOIN platforms p ON p.id = r.platform_id").
+		Where(models.VisibleOnly)
+	if lang := strings.ToLower(c.QueryParam("lang")); lang != "" {
+		if !isLanguageCode(lang) {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": "lang must be a two-letter ISO 639-1 code"})
+		}
+		configs = []string{models.SearchConfig(lang)}
+		query = query.Where("r.language = ?", lang)
+	}
+	match, n := models.SearchMatch(configs)
+	matchArgs := make([]interface{}, n)
+	for i := range matchArgs {
+		matchArgs[i] = q
+	}
+	query = query.Where(match, matchArgs...)
+
+	if v := c.QueryParam("hotel_id"); v != "" {
+		hotelID, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid hotel_id"})
+		}
+		query = query.Where("r.hotel_id = ?", hotelID)
+	}
+	if name := c.QueryParam("platform"); name != "" {
+		platform, err := platformByName(db, name)
+		if errors.Is(err, errUnknownPlatform) {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": "Unknown platform"})
+		} else if err != nil {
+			return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch platform"})
+		}
+		query = query.Where("r.platform_id = ?", platform.ID)
+	}
+	// Days are each hotel's own calendar days; days counts back from today
+	// in UTC.
+	from, to, err := reviewDateRange(c, models.Hotel{})
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	if from != nil {
+		query = query.Where("r.review_local_date >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("r.review_local_date <= ?", *to)
+	}
+
+	results := models.ReviewSearchResults{Query: q, Page: page, Limit: limit}
+	if err := query.Session(&gorm.Session{}).Count(&results.Total).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to count search results"})
+	}
+
+	// Hits are ranked and highlighted with the query in their own language.
+	cfg := models.SearchConfigOf("r.language")
+	tsquery := "websearch_to_tsquery(" + cfg + ", ?)"
+	if err := query.
+		Select(`r.id, r.hotel_id, h.name AS hotel_name, p.name AS platform, r.rating, r.normalized_rating,
+               r.review_date, r.language,
+               ts_headline(`+cfg+`, r.review_title, `+tsquery+`, ?) AS title_highlight,
+               ts_headline(`+cfg+`, r.review_text, `+tsquery+`, ?) AS snippet,
+               ts_rank_cd(r.search_vector, `+tsquery+`) AS rank`, q, titleOptions, q, snippetOptions, q).
+		Order("rank DESC, r.id DESC").
+		Limit(limit).Offset((page - 1) * limit).
+		Scan(&results.Results).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to search reviews"})
+	}
+	if results.Results == nil {
+		results.Results = []models.ReviewSearchHit{}
+	}
+	for i := range results.Results {
+		hit := &results.Results[i]
+		hit.TitleHighlight = highlighter.Replace(html.EscapeString(hit.TitleHighlight))
+		hit.Snippet = highlighter.Replace(html.EscapeString(hit.Snippet))
+	}
+	return c.JSON(http.StatusOK, results)
+}
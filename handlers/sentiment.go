@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"review-system/internal/sentiment"
+	"review-system/models"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// Default normalized ratings (0–10) a review's text must disagree with to be
+// reported.
+const (
+	defaultMismatchHighRating = 7
+	defaultMismatchLowRating  = 4
+)
+
+// GetSentimentMismatches godoc
+// @Summary List reviews whose text sentiment disagrees with their rating
+// @Description Reports reviews with negative text and a normalized rating at or above high_rating,
+// @Description or positive text and a rating at or below low_rating, strongest disagreement first.
+// @Description Requires a token with the analyst role.
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Param hotel_id query int false "Only this hotel"
+// @Param platform query string false "Only this platform"
+// @Param high_rating query number false "Normalized rating negative text is reported at or above" default(7)
+// @Param low_rating query number false "Normalized rating positive text is reported at or below" default(4)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Reviews per page" default(20)
+// @Success 200 {object} models.SentimentMismatchReport
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/sentiment-mismatches [get]
+func GetSentimentMismatches(c echo.Context) error {
+	page, limit := 1, 20
+	if p, err := strconv.Atoi(c.QueryParam("page")); err == nil && p > 0 {
+		page = p
+	}
+	if l, err := strconv.Atoi(c.QueryParam("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+
+	high, low := float32(defaultMismatchHighRating), float32(defaultMismatchLowRating)
+	if v := c.QueryParam("high_rating"); v != "" {
+		f, err := strconv.ParseFloat(v, 32)
+		if err != nil || f < 0 || f > 10 {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": "high_rating must be between 0 and 10"})
+		}
+		high = float32(f)
+	}
+	if v := c.QueryParam("low_rating"); v != "" {
+		f, err := strconv.ParseFloat(v, 32)
+		if err != nil || f < 0 || f > 10 {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": "low_rating must be between 0 and 10"})
+		}
+		low = float32(f)
+	}
+
+	db := models.GetDB()
+	query := db.Table("reviews r").
+		Joins("JOIN hotels h ON h.id = r.hotel_id").
+		Joins("JOIN platforms p ON p.id = r.platform_id").
+		Where("(r.sentiment_label = ? AND r.normalized_rating >= ?) OR (r.sentiment_label = ? AND r.normalized_rating <= ?)",
+			sentiment.Negative, high, sentiment.Positive, low)
+	if v := c.QueryParam("hotel_id"); v != "" {
+		hotelID, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid hotel_id"})
+		}
+		query = query.Where("r.hotel_id = ?", hotelID)
+	}
+	if platform := c.QueryParam("platform"); platform != "" {
+		query = query.Where("LOWER(p.name) = LOWER(?)", platform)
+	}
+
+	report := models.SentimentMismatchReport{HighRating: high, LowRating: low, Page: page, Limit: limit}
+	if err := query.Session(&gorm.Session{}).Count(&report.Total).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to count mismatches"})
+	}
+
+	// The rating mapped onto -1..1 is compared with the sentiment score.
+	if err := query.
+		Select(`r.id, r.hotel_review_id, r.hotel_id, h.name AS hotel_name, p.name AS platform,
+               r.rating, r.normalized_rating, r.review_title, r.review_text, r.language,
+               r.sentiment_score, r.sentiment_label,
+               CASE WHEN r.sentiment_label = ? THEN 'negative_text_high_rating'
+                    ELSE 'positive_text_low_rating' END AS mismatch`, sentiment.Negative).
+		Order("ABS(r.normalized_rating / 5 - 1 - r.sentiment_score) DESC, r.id").
+		Limit(limit).Offset((page - 1) * limit).
+		Scan(&report.Reviews).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch mismatches"})
+	}
+	if report.Reviews == nil {
+		report.Reviews = []models.SentimentMismatch{}
+	}
+	return c.JSON(http.StatusOK, report)
+}
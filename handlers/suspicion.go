@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"review-system/internal/fraud"
+	"review-system/models"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// GetSuspiciousReviews godoc
+// @Summary List reviews that look like spam or fraud
+// @Description Reports reviews whose suspicion score is at or above min_score, most suspicious first,
+// @Description with the fraud rules that flagged them: rating_burst, template_text,
+// @Description extreme_rating_short_text, single_review_account and repeat_reviewer.
+// @Description Requires a token with the moderator role.
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Param hotel_id query int false "Only this hotel"
+// @Param platform query string false "Only this platform"
+// @Param reason query string false "Only reviews flagged by this rule"
+// @Param min_score query number false "Lowest suspicion score reported, 0 to 1; SUSPICION_THRESHOLD by default"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Reviews per page" default(20)
+// @Success 200 {object} models.SuspiciousReviewReport
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/suspicious-reviews [get]
+func GetSuspiciousReviews(c echo.Context) error {
+	page, limit := 1, 20
+	if p, err := strconv.Atoi(c.QueryParam("page")); err == nil && p > 0 {
+		page = p
+	}
+	if l, err := strconv.Atoi(c.QueryParam("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+
+	minScore := float32(models.SuspicionThreshold())
+	if v := c.QueryParam("min_score"); v != "" {
+		f, err := strconv.ParseFloat(v, 32)
+		if err != nil || f < 0 || f > 1 {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": "min_score must be between 0 and 1"})
+		}
+		minScore = float32(f)
+	}
+
+	db := models.GetDB()
+	query := db.Table("reviews r").
+		Joins("JOIN hotels h ON h.id = r.hotel_id").
+		Joins("JOIN platforms p ON p.id = r.platform_id").
+		Where("r.suspicion_score >= ? AND r.suspicion_reasons <> ''", minScore)
+	if v := c.QueryParam("hotel_id"); v != "" {
+		hotelID, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": "Invalid hotel_id"})
+		}
+		query = query.Where("r.hotel_id = ?", hotelID)
+	}
+	if platform := c.QueryParam("platform"); platform != "" {
+		query = query.Where("LOWER(p.name) = LOWER(?)", platform)
+	}
+	if reason := c.QueryParam("reason"); reason != "" {
+		if !isFraudReason(reason) {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": "Unknown reason"})
+		}
+		query = query.Where("',' || r.suspicion_reasons || ',' LIKE ?", "%,"+reason+",%")
+	}
+
+	report := models.SuspiciousReviewReport{MinScore: minScore, Page: page, Limit: limit}
+	if err := query.Session(&gorm.Session{}).Count(&report.Total).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to count suspicious reviews"})
+	}
+
+	if err := query.
+		Select(`r.id, r.hotel_review_id, r.hotel_id, h.name AS hotel_name, p.name AS platform,
+               r.rating, r.normalized_rating, r.review_title, r.review_text, r.review_date,
+               r.reviewer_profile_id, r.duplicate_cluster_id, r.suspicion_score, r.suspicion_reasons`).
+		Order("r.suspicion_score DESC, r.id").
+		Limit(limit).Offset((page - 1) * limit).
+		Scan(&report.Reviews).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Failed to fetch suspicious reviews"})
+	}
+	if report.Reviews == nil {
+		report.Reviews = []models.SuspiciousReview{}
+	}
+	for i := range report.Reviews {
+		report.Reviews[i].Reasons = strings.Split(report.Reviews[i].SuspicionReasons, ",")
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+func isFraudReason(reason string) bool {
+	for _, r := range fraud.Rules {
+		if r.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// suspicionFilter reads the exclude_suspicious query parameter and returns
+// the condition that leaves out reviews scored at or above the suspicion
+// threshold, or "" when it is off.
+func suspicionFilter(c echo.Context) (string, []interface{}, error) {
+	v := c.QueryParam("exclude_suspicious")
+	if v == "" {
+		return "", nil, nil
+	}
+	on, err := strconv.ParseBool(v)
+	if err != nil {
+		return "", nil, errors.New("exclude_suspicious must be true or false")
+	}
+	if !on {
+		return "", nil, nil
+	}
+	return "AND r.suspicion_score < ?", []interface{}{models.SuspicionThreshold()}, nil
+}
@@ -0,0 +1,119 @@
+// Package aspects tags the sentences of a review with the hotel aspects they
+// talk about and how they talk about them. Each sentence is split into
+// clauses at contrast words, so "Staff was friendly but the room was noisy"
+// praises the staff and complains about the room and the noise. A clause's
+// polarity comes from the sentiment lexicon for the review's language.
+package aspects
+
+import (
+	"strings"
+	"sync"
+
+	"review-system/internal/sentiment"
+)
+
+// Aspects reviewers comment on.
+const (
+	Staff           = "staff"
+	Room            = "room"
+	Cleanliness     = "cleanliness"
+	Location        = "location"
+	Noise           = "noise"
+	Breakfast       = "breakfast"
+	CheckIn         = "check_in"
+	Value           = "value"
+	Wifi            = "wifi"
+	AirConditioning = "air_conditioning"
+)
+
+// All lists every aspect.
+var All = []string{Staff, Room, Cleanliness, Location, Noise, Breakfast, CheckIn, Value, Wifi, AirConditioning}
+
+// Keywords maps a lowercased word or phrase of up to three words to the
+// aspect it names.
+type Keywords map[string]string
+
+const maxPhraseWords = 3
+
+var (
+	mu       sync.RWMutex
+	keywords = map[string]Keywords{}
+)
+
+// Register makes kw the keywords for lang, replacing any registered before.
+// Mentions are only extracted for languages that also have a sentiment
+// lexicon.
+func Register(lang string, kw Keywords) {
+	mu.Lock()
+	defer mu.Unlock()
+	keywords[lang] = kw
+}
+
+// Mention is one aspect named in one sentence of a review.
+type Mention struct {
+	Aspect   string
+	Polarity string  // sentiment.Positive, Neutral or Negative
+	Score    float64 // -1 to 1
+	Sentence string
+}
+
+// Extract returns the aspects mentioned in a review's title and text, at
+// most once per aspect and sentence. Text in an undetermined language ("")
+// is read as English.
+func Extract(title, text, lang string) []Mention {
+	if lang == "" {
+		lang = "en"
+	}
+	mu.RLock()
+	kw := keywords[lang]
+	mu.RUnlock()
+	if kw == nil {
+		return nil
+	}
+
+	var mentions []Mention
+	for _, sentence := range append(sentiment.Sentences(title), sentiment.Sentences(text)...) {
+		sentence = strings.TrimSpace(sentence)
+		seen := map[string]bool{}
+		for _, clause := range sentiment.Clauses(sentiment.Words(sentence), lang) {
+			named := kw.find(clause)
+			if len(named) == 0 {
+				continue
+			}
+			res, ok := sentiment.ScoreClause(clause, lang)
+			if !ok {
+				return nil
+			}
+			for _, aspect := range named {
+				if seen[aspect] {
+					continue
+				}
+				seen[aspect] = true
+				mentions = append(mentions, Mention{
+					Aspect:   aspect,
+					Polarity: res.Label,
+					Score:    res.Score,
+					Sentence: sentence,
+				})
+			}
+		}
+	}
+	return mentions
+}
+
+// find returns the aspects named in words, matching the longest phrase at
+// each position.
+func (kw Keywords) find(words []string) []string {
+	var named []string
+	for i := 0; i < len(words); {
+		n := min(maxPhraseWords, len(words)-i)
+		for ; n > 0; n-- {
+			if aspect, ok := kw[strings.Join(words[i:i+n], " ")]; ok {
+				named = append(named, aspect)
+				break
+			}
+		}
+		i += max(n, 1)
+	}
+	return named
+}
@@ -0,0 +1,109 @@
+package aspects
+
+// Built-in keywords, for the languages the sentiment package ships a lexicon
+// for.
+func init() {
+	Register("en", english)
+	Register("fr", french)
+	Register("es", spanish)
+	Register("de", german)
+	Register("vi", vietnamese)
+	Register("id", indonesian)
+}
+
+var english = Keywords{
+	"staff": Staff, "service": Staff, "reception": Staff, "receptionist": Staff,
+	"front desk": Staff, "employees": Staff, "manager": Staff, "concierge": Staff,
+	"housekeeping": Staff, "waiter": Staff, "waiters": Staff,
+
+	"room": Room, "rooms": Room, "bed": Room, "beds": Room, "bathroom": Room,
+	"shower": Room, "suite": Room, "pillow": Room, "pillows": Room, "view": Room,
+	"balcony": Room, "towels": Room,
+
+	"clean": Cleanliness, "cleanliness": Cleanliness, "dirty": Cleanliness, "spotless": Cleanliness,
+	"filthy": Cleanliness, "dust": Cleanliness, "dusty": Cleanliness, "stained": Cleanliness,
+	"well maintained": Cleanliness,
+
+	"location": Location, "located": Location, "downtown": Location, "neighborhood": Location,
+	"neighbourhood": Location, "beach": Location, "walking distance": Location, "area": Location,
+
+	"noise": Noise, "noisy": Noise, "loud": Noise, "quiet": Noise, "traffic": Noise,
+	"thin walls": Noise,
+
+	"breakfast": Breakfast, "buffet": Breakfast, "coffee": Breakfast,
+
+	"check in": CheckIn, "checkin": CheckIn, "check out": CheckIn, "checkout": CheckIn, "queue": CheckIn,
+
+	"price": Value, "value": Value, "money": Value, "expensive": Value, "cheap": Value,
+	"overpriced": Value, "affordable": Value,
+
+	"wifi": Wifi, "wi fi": Wifi, "internet": Wifi,
+
+	"ac": AirConditioning, "air conditioning": AirConditioning,
+	"air conditioner": AirConditioning, "aircon": AirConditioning,
+}
+
+var french = Keywords{
+	"personnel": Staff, "accueil": Staff, "service": Staff, "réception": Staff, "équipe": Staff,
+	"chambre": Room, "chambres": Room, "lit": Room, "salle de bain": Room, "douche": Room,
+	"propre": Cleanliness, "propreté": Cleanliness, "sale": Cleanliness,
+	"emplacement": Location, "situation": Location, "situé": Location, "quartier": Location,
+	"bruit": Noise, "bruyant": Noise, "bruyante": Noise, "calme": Noise,
+	"petit déjeuner": Breakfast,
+	"enregistrement": CheckIn, "arrivée": CheckIn, "départ": CheckIn,
+	"prix": Value, "rapport qualité prix": Value, "cher": Value,
+	"wifi": Wifi, "internet": Wifi,
+	"climatisation": AirConditioning, "clim": AirConditioning,
+}
+
+var spanish = Keywords{
+	"personal": Staff, "servicio": Staff, "recepción": Staff, "recepcionista": Staff,
+	"habitación": Room, "habitaciones": Room, "cama": Room, "baño": Room, "ducha": Room,
+	"limpio": Cleanliness, "limpia": Cleanliness, "limpieza": Cleanliness, "sucio": Cleanliness, "sucia": Cleanliness,
+	"ubicación": Location, "ubicado": Location, "zona": Location, "barrio": Location,
+	"ruido": Noise, "ruidoso": Noise, "ruidosa": Noise, "tranquilo": Noise,
+	"desayuno": Breakfast,
+	"registro": CheckIn, "entrada": CheckIn, "salida": CheckIn,
+	"precio": Value, "caro": Value, "calidad precio": Value,
+	"wifi": Wifi, "internet": Wifi,
+	"aire acondicionado": AirConditioning,
+}
+
+var german = Keywords{
+	"personal": Staff, "service": Staff, "rezeption": Staff, "mitarbeiter": Staff,
+	"zimmer": Room, "bett": Room, "bad": Room, "badezimmer": Room, "dusche": Room,
+	"sauber": Cleanliness, "sauberkeit": Cleanliness, "schmutzig": Cleanliness, "dreckig": Cleanliness,
+	"lage": Location, "gelegen": Location, "umgebung": Location,
+	"lärm": Noise, "laut": Noise, "ruhig": Noise,
+	"frühstück": Breakfast, "buffet": Breakfast,
+	"check in": CheckIn, "anreise": CheckIn, "abreise": CheckIn,
+	"preis": Value, "teuer": Value, "preis leistung": Value,
+	"wlan": Wifi, "wifi": Wifi, "internet": Wifi,
+	"klimaanlage": AirConditioning,
+}
+
+var vietnamese = Keywords{
+	"nhân viên": Staff, "phục vụ": Staff, "lễ tân": Staff,
+	"phòng": Room, "giường": Room, "phòng tắm": Room,
+	"sạch sẽ": Cleanliness, "sạch": Cleanliness, "bẩn": Cleanliness, "dơ": Cleanliness,
+	"vị trí": Location, "địa điểm": Location, "trung tâm": Location,
+	"ồn": Noise, "ồn ào": Noise, "yên tĩnh": Noise,
+	"bữa sáng": Breakfast, "ăn sáng": Breakfast,
+	"nhận phòng": CheckIn, "trả phòng": CheckIn, "check in": CheckIn,
+	"giá": Value, "giá cả": Value, "đắt": Value,
+	"wifi": Wifi, "mạng": Wifi,
+	"điều hòa": AirConditioning, "máy lạnh": AirConditioning,
+}
+
+var indonesian = Keywords{
+	"staf": Staff, "pelayanan": Staff, "resepsionis": Staff, "karyawan": Staff,
+	"kamar": Room, "kasur": Room, "kamar mandi": Room,
+	"bersih": Cleanliness, "kebersihan": Cleanliness, "kotor": Cleanliness,
+	"lokasi": Location, "strategis": Location,
+	"berisik": Noise, "bising": Noise, "tenang": Noise,
+	"sarapan": Breakfast, "prasmanan": Breakfast,
+	"check in": CheckIn, "check out": CheckIn,
+	"harga": Value, "mahal": Value, "murah": Value,
+	"wifi": Wifi, "internet": Wifi,
+	"ac": AirConditioning,
+}
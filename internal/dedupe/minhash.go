@@ -0,0 +1,144 @@
+// Package dedupe estimates how similar two review texts are with MinHash
+// signatures over word shingles. A signature is a fixed 256 bytes however
+// long the text, so it can be stored with the review and compared later
+// without the text.
+package dedupe
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+const (
+	// NumHashes is the number of hash functions in a signature. The
+	// similarity estimate is within about ±0.06 of the true Jaccard
+	// similarity.
+	NumHashes = 64
+	// ShingleWords is the number of consecutive words in a shingle.
+	ShingleWords = 3
+	// MinWords is the shortest text, in words, that gets a signature. Short
+	// texts such as "Great stay, friendly staff" are written word for word by
+	// different guests, so they would look like duplicates.
+	MinWords = 10
+	// Bands is the number of bands a signature is split into for
+	// locality-sensitive hashing, each BandRows hashes long. Two texts share
+	// a band with probability 1-(1-s^BandRows)^Bands at similarity s: about
+	// 0.99 at 0.7, 0.64 at 0.5 and 0.12 at 0.3.
+	Bands    = 16
+	BandRows = NumHashes / Bands
+)
+
+// Signature is a MinHash signature.
+type Signature []uint32
+
+// seeds holds one seed per hash function, fixed so signatures stored by one
+// version can be compared with those computed by the next.
+var seeds = func() [NumHashes]uint64 {
+	var s [NumHashes]uint64
+	x := uint64(0x5eed)
+	for i := range s {
+		x += 0x9e3779b97f4a7c15
+		s[i] = mix(x)
+	}
+	return s
+}()
+
+// Sign returns the signature of text, or nil when it has fewer than
+// MinWords words.
+func Sign(text string) Signature {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) < MinWords {
+		return nil
+	}
+
+	sig := make(Signature, NumHashes)
+	for i := range sig {
+		sig[i] = math.MaxUint32
+	}
+	for i := 0; i+ShingleWords <= len(words); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:i+ShingleWords], " ")))
+		shingle := h.Sum64()
+		for j, seed := range seeds {
+			if v := uint32(mix(shingle ^ seed)); v < sig[j] {
+				sig[j] = v
+			}
+		}
+	}
+	return sig
+}
+
+// Similarity estimates the Jaccard similarity of the shingles behind two
+// signatures, from 0 to 1. Signatures of different lengths are 0.
+func Similarity(a, b Signature) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	same := 0
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	return float64(same) / float64(len(a))
+}
+
+// BandHashes hashes each band of the signature together with its position,
+// so that equal values in different bands do not collide. Signatures that
+// share no band hash are unlikely to be similar and need not be compared.
+func (s Signature) BandHashes() []int64 {
+	if len(s) != NumHashes {
+		return nil
+	}
+	out := make([]int64, Bands)
+	buf := make([]byte, 4*(BandRows+1))
+	for band := range out {
+		binary.BigEndian.PutUint32(buf, uint32(band))
+		for i, v := range s[band*BandRows : (band+1)*BandRows] {
+			binary.BigEndian.PutUint32(buf[4*(i+1):], v)
+		}
+		h := fnv.New64a()
+		h.Write(buf)
+		out[band] = int64(h.Sum64())
+	}
+	return out
+}
+
+// Bytes encodes the signature for storage, nil for a nil signature.
+func (s Signature) Bytes() []byte {
+	if s == nil {
+		return nil
+	}
+	b := make([]byte, 4*len(s))
+	for i, v := range s {
+		binary.BigEndian.PutUint32(b[4*i:], v)
+	}
+	return b
+}
+
+// FromBytes decodes a signature written by Bytes.
+func FromBytes(b []byte) Signature {
+	if len(b) == 0 || len(b)%4 != 0 {
+		return nil
+	}
+	s := make(Signature, len(b)/4)
+	for i := range s {
+		s[i] = binary.BigEndian.Uint32(b[4*i:])
+	}
+	return s
+}
+
+// mix is the splitmix64 finalizer.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,134 @@
+package dedupe
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const review = "The room was spotless and the bed very comfortable, breakfast had plenty of choice and the staff at reception helped us book a boat trip on the bay."
+
+func TestSign(t *testing.T) {
+	if s := Sign("Great stay, friendly staff, would come back"); s != nil {
+		t.Errorf("Sign of a short text = %v, want nil", s)
+	}
+	if s := Sign(strings.Repeat("word ", MinWords)); len(s) != NumHashes {
+		t.Errorf("Sign of %d words has %d hashes, want %d", MinWords, len(s), NumHashes)
+	}
+	if !slices.Equal(Sign(review), Sign(strings.ToUpper(review)+"!!")) {
+		t.Error("Sign depends on case or punctuation")
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name     string
+		other    string
+		min, max float64
+		band     bool
+	}{
+		{"identical", review, 1, 1, true},
+		{"one word changed", strings.Replace(review, "boat", "kayak", 1), 0.7, 1, true},
+		{"sentence appended", review + " We would definitely stay here again next year.", 0.6, 1, true},
+		{"unrelated", "Check-in took over an hour, the air conditioning was broken and nobody came to fix it despite three calls to the front desk.", 0, 0.2, false},
+	}
+	a := Sign(review)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Sign(tt.other)
+			if sim := Similarity(a, b); sim < tt.min || sim > tt.max {
+				t.Errorf("Similarity = %.2f, want %.2f to %.2f", sim, tt.min, tt.max)
+			}
+			if band := sharesBand(a, b); band != tt.band {
+				t.Errorf("shares a band = %v, want %v", band, tt.band)
+			}
+		})
+	}
+
+	if sim := Similarity(a, nil); sim != 0 {
+		t.Errorf("Similarity with nil = %v, want 0", sim)
+	}
+	if sim := Similarity(a, a[:NumHashes/2]); sim != 0 {
+		t.Errorf("Similarity of different lengths = %v, want 0", sim)
+	}
+}
+
+// TestBandThreshold checks that banding finds nearly every pair above the
+// default duplicate threshold of 0.7 and few pairs well below it.
+func TestBandThreshold(t *testing.T) {
+	const pairs = 200
+	tests := []struct {
+		name     string
+		replaced int     // trailing words of 100 replaced in the second text
+		jaccard  float64 // (98-replaced)/(98+replaced)
+		min, max float64 // share of pairs with a common band
+	}{
+		{"above threshold", 14, 0.75, 0.95, 1},
+		{"at threshold", 17, 0.70, 0.90, 1},
+		{"well below threshold", 59, 0.25, 0, 0.2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found := 0
+			for p := 0; p < pairs; p++ {
+				words := make([]string, 100)
+				for i := range words {
+					words[i] = fmt.Sprintf("w%dx%d", p, i)
+				}
+				a := Sign(strings.Join(words, " "))
+				for i := len(words) - tt.replaced; i < len(words); i++ {
+					words[i] = fmt.Sprintf("v%dx%d", p, i)
+				}
+				if sharesBand(a, Sign(strings.Join(words, " "))) {
+					found++
+				}
+			}
+			if share := float64(found) / pairs; share < tt.min || share > tt.max {
+				t.Errorf("%.0f%% of pairs at similarity %.2f share a band, want %.0f%% to %.0f%%",
+					share*100, tt.jaccard, tt.min*100, tt.max*100)
+			}
+		})
+	}
+}
+
+func TestBandHashes(t *testing.T) {
+	s := Sign(review)
+	bands := s.BandHashes()
+	if len(bands) != Bands {
+		t.Fatalf("got %d bands, want %d", len(bands), Bands)
+	}
+	// A band's hash depends on its position, so a signature whose bands all
+	// hold the same values still gets distinct hashes.
+	same := make(Signature, NumHashes)
+	if h := same.BandHashes(); h[0] == h[1] {
+		t.Error("equal bands at different positions hash the same")
+	}
+	if h := s[:BandRows].BandHashes(); h != nil {
+		t.Errorf("BandHashes of a short signature = %v, want nil", h)
+	}
+}
+
+func TestBytes(t *testing.T) {
+	s := Sign(review)
+	b := s.Bytes()
+	if len(b) != 4*NumHashes {
+		t.Errorf("encoded %d bytes, want %d", len(b), 4*NumHashes)
+	}
+	if got := FromBytes(b); !slices.Equal(got, s) {
+		t.Error("FromBytes(Bytes()) does not round-trip")
+	}
+	if Signature(nil).Bytes() != nil || FromBytes(nil) != nil || FromBytes([]byte{1, 2, 3}) != nil {
+		t.Error("nil or malformed signatures should encode and decode to nil")
+	}
+}
+
+func sharesBand(a, b Signature) bool {
+	bb := b.BandHashes()
+	for i, h := range a.BandHashes() {
+		if bb != nil && bb[i] == h {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+// Package fraud scores how likely a review is to be spam or part of a
+// manipulation campaign such as review bombing or a burst of fake positive
+// reviews. Each rule that fires adds a reason, and the rules' weights are
+// combined as independent evidence: score = 1 - Π(1 - weight).
+package fraud
+
+import "review-system/internal/dedupe"
+
+// Reasons.
+const (
+	RatingBurst      = "rating_burst"
+	TemplateText     = "template_text"
+	ExtremeShortText = "extreme_rating_short_text"
+	SingleReviewUser = "single_review_account"
+	RepeatReviewer   = "repeat_reviewer"
+)
+
+const (
+	// DefaultThreshold is the score from which a review counts as suspicious.
+	DefaultThreshold = 0.5
+
+	BurstMinReviews   = 5   // reviews in a day before it can be a burst
+	BurstFactor       = 3   // times the hotel's usual daily volume
+	BurstMinHistory   = 7   // days of reviews before the usual volume is known
+	TemplateMinCopies = 3   // reviews sharing one text, counting this one
+	ShortTextWords    = 5   // texts under this many words are short
+	RepeatMinReviews  = 3   // reviews by one reviewer for one hotel
+	ExtremeLow        = 2   // normalized ratings at or below are extreme
+	ExtremeHigh       = 9.5 // normalized ratings at or above are extreme
+
+	// TemplateMinWords is the shortest text that can be a template. Guests
+	// write short texts such as "Room was clean and well maintained." word
+	// for word, as they do for near-duplicates.
+	TemplateMinWords = dedupe.MinWords
+)
+
+// Signals are the facts about a review and its neighbours the rules read.
+type Signals struct {
+	NormalizedRating float32
+	TextWords        int
+	// Reviews of the hotel on the review's local date, including it, the
+	// hotel's average per day over the weeks before, and how many days of
+	// reviews that average covers.
+	DayReviews    int
+	BaselineDaily float64
+	HistoryDays   int
+	// Other reviews of the hotel with the same text, and the size of the
+	// review's near-duplicate cluster (0 if none).
+	IdenticalTexts int
+	ClusterSize    int
+	// Reviews the platform says the reviewer has written, 0 if unknown, and
+	// the reviewer's reviews of this hotel, including this one.
+	ReviewerReviews      int
+	ReviewerHotelReviews int
+}
+
+// Extreme reports whether the rating is at either end of the scale.
+func (s Signals) Extreme() bool {
+	return s.NormalizedRating <= ExtremeLow || s.NormalizedRating >= ExtremeHigh
+}
+
+// Rule is one heuristic.
+type Rule struct {
+	Reason      string
+	Description string
+	Weight      float64
+	Applies     func(Signals) bool
+}
+
+// Rules are the heuristics every review is scored with.
+var Rules = []Rule{
+	{
+		Reason:      RatingBurst,
+		Description: "Extreme rating on a day with an unusual burst of reviews for the hotel",
+		Weight:      0.4,
+		Applies: func(s Signals) bool {
+			return s.Extreme() && s.HistoryDays >= BurstMinHistory && s.DayReviews >= BurstMinReviews &&
+				float64(s.DayReviews) >= BurstFactor*s.BaselineDaily
+		},
+	},
+	{
+		Reason:      TemplateText,
+		Description: "Same or near-identical text as several other reviews of the hotel",
+		Weight:      0.35,
+		Applies: func(s Signals) bool {
+			return s.TextWords >= TemplateMinWords && (s.IdenticalTexts+1 >= TemplateMinCopies || s.ClusterSize >= TemplateMinCopies)
+		},
+	},
+	{
+		Reason:      ExtremeShortText,
+		Description: "Extreme rating with next to no text",
+		Weight:      0.3,
+		Applies: func(s Signals) bool {
+			return s.Extreme() && s.TextWords < ShortTextWords
+		},
+	},
+	{
+		Reason:      SingleReviewUser,
+		Description: "Extreme rating from an account with no other reviews",
+		Weight:      0.15,
+		Applies: func(s Signals) bool {
+			return s.Extreme() && s.ReviewerReviews == 1
+		},
+	},
+	{
+		Reason:      RepeatReviewer,
+		Description: "Reviewer has reviewed the hotel several times in a few weeks",
+		Weight:      0.35,
+		Applies: func(s Signals) bool {
+			return s.ReviewerHotelReviews >= RepeatMinReviews
+		},
+	},
+}
+
+// Score returns the suspicion score, from 0 to 1, and the reasons behind it
+// in rule order.
+func Score(s Signals) (float64, []string) {
+	clean := 1.0
+	var reasons []string
+	for _, r := range Rules {
+		if r.Applies(s) {
+			clean *= 1 - r.Weight
+			reasons = append(reasons, r.Reason)
+		}
+	}
+	return 1 - clean, reasons
+}
@@ -0,0 +1,71 @@
+package fraud
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestScore(t *testing.T) {
+	// burst is a 1-star review on a busy day at a hotel with a month of
+	// history and 1 review a day.
+	burst := Signals{NormalizedRating: 1, TextWords: 20, DayReviews: 5, BaselineDaily: 1, HistoryDays: 28}
+	with := func(s Signals, change func(*Signals)) Signals {
+		change(&s)
+		return s
+	}
+
+	tests := []struct {
+		name    string
+		signals Signals
+		reasons []string
+	}{
+		{"nothing unusual", Signals{NormalizedRating: 8, TextWords: 40, DayReviews: 1, BaselineDaily: 1, HistoryDays: 28}, nil},
+
+		{"burst", burst, []string{RatingBurst}},
+		{"burst at high extreme", with(burst, func(s *Signals) { s.NormalizedRating = ExtremeHigh }), []string{RatingBurst}},
+		{"burst needs an extreme rating", with(burst, func(s *Signals) { s.NormalizedRating = 7 }), nil},
+		{"burst needs enough reviews", with(burst, func(s *Signals) { s.DayReviews = BurstMinReviews - 1; s.BaselineDaily = 0.5 }), nil},
+		{"burst at exactly the factor", with(burst, func(s *Signals) { s.DayReviews = 6; s.BaselineDaily = 2 }), []string{RatingBurst}},
+		{"burst below the factor", with(burst, func(s *Signals) { s.DayReviews = 6; s.BaselineDaily = 2.1 }), nil},
+		{"burst needs history", with(burst, func(s *Signals) { s.HistoryDays = BurstMinHistory - 1 }), nil},
+		{"burst with just enough history", with(burst, func(s *Signals) { s.HistoryDays = BurstMinHistory }), []string{RatingBurst}},
+
+		{"template by identical text", Signals{NormalizedRating: 8, TextWords: TemplateMinWords, IdenticalTexts: TemplateMinCopies - 1}, []string{TemplateText}},
+		{"template needs enough copies", Signals{NormalizedRating: 8, TextWords: TemplateMinWords, IdenticalTexts: TemplateMinCopies - 2}, nil},
+		{"template by cluster", Signals{NormalizedRating: 8, TextWords: 30, ClusterSize: TemplateMinCopies}, []string{TemplateText}},
+		{"template cluster too small", Signals{NormalizedRating: 8, TextWords: 30, ClusterSize: TemplateMinCopies - 1}, nil},
+		{"template ignores short texts", Signals{NormalizedRating: 8, TextWords: TemplateMinWords - 1, IdenticalTexts: 10}, nil},
+
+		{"short extreme text", Signals{NormalizedRating: ExtremeLow, TextWords: ShortTextWords - 1}, []string{ExtremeShortText}},
+		{"short text long enough", Signals{NormalizedRating: ExtremeLow, TextWords: ShortTextWords}, nil},
+		{"short text not extreme", Signals{NormalizedRating: 5, TextWords: 0}, nil},
+
+		{"single review account", Signals{NormalizedRating: 10, TextWords: 20, ReviewerReviews: 1}, []string{SingleReviewUser}},
+		{"unknown review count", Signals{NormalizedRating: 10, TextWords: 20, ReviewerReviews: 0}, nil},
+
+		{"repeat reviewer", Signals{NormalizedRating: 8, TextWords: 20, ReviewerHotelReviews: RepeatMinReviews}, []string{RepeatReviewer}},
+		{"repeat reviewer below minimum", Signals{NormalizedRating: 8, TextWords: 20, ReviewerHotelReviews: RepeatMinReviews - 1}, nil},
+
+		{"several rules", Signals{NormalizedRating: 1, TextWords: 2, ReviewerReviews: 1}, []string{ExtremeShortText, SingleReviewUser}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, reasons := Score(tt.signals)
+			if !slices.Equal(reasons, tt.reasons) {
+				t.Errorf("reasons = %v, want %v", reasons, tt.reasons)
+			}
+		})
+	}
+}
+
+func TestScoreCombinesWeights(t *testing.T) {
+	// Extreme short text (0.3) and single review account (0.15) are
+	// independent evidence: 1 - 0.7 × 0.85.
+	score, _ := Score(Signals{NormalizedRating: 1, TextWords: 2, ReviewerReviews: 1})
+	if want := 0.405; score < want-1e-9 || score > want+1e-9 {
+		t.Errorf("score = %v, want %v", score, want)
+	}
+	if score, reasons := Score(Signals{NormalizedRating: 8, TextWords: 20}); score != 0 || reasons != nil {
+		t.Errorf("clean review scored %v %v", score, reasons)
+	}
+}
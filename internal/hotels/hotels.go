@@ -0,0 +1,370 @@
+// Package hotels maintains canonical hotel identity: resolving a provider's
+// hotel ID to our hotel, proposing cross-platform matches, and the admin
+// operations that confirm, merge or split canonical hotels.
+package hotels
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"review-system/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotFound = errors.New("not found")
+	ErrInvalid  = errors.New("invalid request")
+)
+
+// ListingInput describes a provider's hotel as it arrives with a review.
+type ListingInput struct {
+	PlatformID uint
+	ExternalID int
+	Name       string
+	Address    string
+	Latitude   *float64
+	Longitude  *float64
+}
+
+// ResolveListing returns the listing for (platform, external ID) and its
+// canonical hotel. The first time a pair is seen a new hotel and listing are
+// created, and likely matches against existing hotels are proposed.
+func ResolveListing(db *gorm.DB, in ListingInput) (models.HotelPlatformListing, models.Hotel, error) {
+	var listing models.HotelPlatformListing
+	var hotel models.Hotel
+
+	err := db.Where("platform_id = ? AND external_id = ?", in.PlatformID, in.ExternalID).First(&listing).Error
+	if err == gorm.ErrRecordNotFound {
+		created, createErr := createListing(db, in)
+		if createErr != nil {
+			// Retry after create error (duplicate from another thread)
+			if dbErr := db.Where("platform_id = ? AND external_id = ?", in.PlatformID, in.ExternalID).First(&listing).Error; dbErr != nil {
+				return listing, hotel, fmt.Errorf("listing fetch failed after duplicate insert (platform=%d, hotelId=%d): %w", in.PlatformID, in.ExternalID, dbErr)
+			}
+		} else {
+			listing = created
+			if err := ProposeMatches(db, listing); err != nil {
+				log.Printf("⚠️  Hotel matching failed for listing %d: %v", listing.ID, err)
+			}
+		}
+	} else if err != nil {
+		return listing, hotel, fmt.Errorf("DB error querying listing (platform=%d, hotelId=%d): %w", in.PlatformID, in.ExternalID, err)
+	}
+
+	if err := db.First(&hotel, listing.HotelID).Error; err != nil {
+		return listing, hotel, fmt.Errorf("DB error loading hotel %d: %w", listing.HotelID, err)
+	}
+	return listing, hotel, nil
+}
+
+func createListing(db *gorm.DB, in ListingInput) (models.HotelPlatformListing, error) {
+	var listing models.HotelPlatformListing
+	err := db.Transaction(func(tx *gorm.DB) error {
+		hotel := models.Hotel{
+			ExternalID:     in.ExternalID,
+			Name:           in.Name,
+			NormalizedName: models.NormalizeHotelName(in.Name),
+			Address:        in.Address,
+			Latitude:       in.Latitude,
+			Longitude:      in.Longitude,
+		}
+		if err := tx.Create(&hotel).Error; err != nil {
+			return err
+		}
+		listing = models.HotelPlatformListing{
+			PlatformID: in.PlatformID,
+			ExternalID: in.ExternalID,
+			HotelID:    hotel.ID,
+			Name:       in.Name,
+			Address:    in.Address,
+			Latitude:   in.Latitude,
+			Longitude:  in.Longitude,
+		}
+		return tx.Create(&listing).Error
+	})
+	return listing, err
+}
+
+// ConfirmMatch applies a proposed match: the listing and its reviews move to
+// the proposed hotel, other proposals for the listing are rejected, and the
+// hotel it leaves is deleted if it has no listings left.
+func ConfirmMatch(db *gorm.DB, candidateID uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var candidate models.HotelMatchCandidate
+		if err := tx.First(&candidate, candidateID).Error; err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("match %d: %w", candidateID, ErrNotFound)
+		} else if err != nil {
+			return err
+		}
+		if candidate.Status != models.MatchProposed {
+			return fmt.Errorf("match %d is already %s: %w", candidateID, candidate.Status, ErrInvalid)
+		}
+
+		var listing models.HotelPlatformListing
+		if err := tx.First(&listing, candidate.ListingID).Error; err != nil {
+			return err
+		}
+		from := listing.HotelID
+
+		if err := moveListings(tx, []uint{listing.ID}, candidate.HotelID); err != nil {
+			return err
+		}
+		if err := tx.Model(&candidate).Update("status", models.MatchConfirmed).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.HotelMatchCandidate{}).
+			Where("listing_id = ? AND id <> ? AND status = ?", listing.ID, candidate.ID, models.MatchProposed).
+			Update("status", models.MatchRejected).Error; err != nil {
+			return err
+		}
+		if err := deleteIfEmpty(tx, from); err != nil {
+			return err
+		}
+		return recomputeHotels(tx, from, candidate.HotelID)
+	})
+}
+
+// RejectMatch marks a proposal as rejected so it is not offered again.
+func RejectMatch(db *gorm.DB, candidateID uint) error {
+	res := db.Model(&models.HotelMatchCandidate{}).
+		Where("id = ? AND status = ?", candidateID, models.MatchProposed).
+		Update("status", models.MatchRejected)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("no pending match %d: %w", candidateID, ErrNotFound)
+	}
+	return nil
+}
+
+// Merge folds the source hotels into target: their listings and reviews
+// move over and the source hotels are deleted.
+func Merge(db *gorm.DB, targetID uint, sourceIDs []uint) error {
+	if len(sourceIDs) == 0 {
+		return fmt.Errorf("no hotels to merge: %w", ErrInvalid)
+	}
+	for _, id := range sourceIDs {
+		if id == targetID {
+			return fmt.Errorf("cannot merge hotel %d into itself: %w", id, ErrInvalid)
+		}
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.Hotel{}).Where("id IN ?", append([]uint{targetID}, sourceIDs...)).Count(&count).Error; err != nil {
+			return err
+		}
+		if int(count) != len(sourceIDs)+1 {
+			return fmt.Errorf("one or more hotels do not exist: %w", ErrNotFound)
+		}
+
+		var listingIDs []uint
+		if err := tx.Model(&models.HotelPlatformListing{}).Where("hotel_id IN ?", sourceIDs).Pluck("id", &listingIDs).Error; err != nil {
+			return err
+		}
+		if err := moveListings(tx, listingIDs, targetID); err != nil {
+			return err
+		}
+		for _, id := range sourceIDs {
+			if err := deleteIfEmpty(tx, id); err != nil {
+				return err
+			}
+		}
+		return recomputeHotels(tx, targetID)
+	})
+}
+
+// Split moves the given listings of a hotel, and their reviews, to a new
+// canonical hotel. name defaults to the first listing's name.
+func Split(db *gorm.DB, hotelID uint, listingIDs []uint, name string) (models.Hotel, error) {
+	var created models.Hotel
+	if len(listingIDs) == 0 {
+		return created, fmt.Errorf("no listings to split off: %w", ErrInvalid)
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var listings []models.HotelPlatformListing
+		if err := tx.Where("hotel_id = ?", hotelID).Order("id").Find(&listings).Error; err != nil {
+			return err
+		}
+		owned := make(map[uint]models.HotelPlatformListing, len(listings))
+		for _, l := range listings {
+			owned[l.ID] = l
+		}
+		for _, id := range listingIDs {
+			if _, ok := owned[id]; !ok {
+				return fmt.Errorf("listing %d does not belong to hotel %d: %w", id, hotelID, ErrInvalid)
+			}
+		}
+		if len(listingIDs) == len(listings) {
+			return fmt.Errorf("cannot split off every listing of hotel %d: %w", hotelID, ErrInvalid)
+		}
+
+		first := owned[listingIDs[0]]
+		if name == "" {
+			name = first.Name
+		}
+		var original models.Hotel
+		if err := tx.First(&original, hotelID).Error; err != nil {
+			return err
+		}
+		created = models.Hotel{
+			Timezone:       original.Timezone,
+			ExternalID:     first.ExternalID,
+			Name:           name,
+			NormalizedName: models.NormalizeHotelName(name),
+			Address:        first.Address,
+			Latitude:       first.Latitude,
+			Longitude:      first.Longitude,
+		}
+		if err := tx.Create(&created).Error; err != nil {
+			return err
+		}
+		if err := moveListings(tx, listingIDs, created.ID); err != nil {
+			return err
+		}
+		return recomputeHotels(tx, hotelID, created.ID)
+	})
+	return created, err
+}
+
+func moveListings(tx *gorm.DB, listingIDs []uint, hotelID uint) error {
+	if len(listingIDs) == 0 {
+		return nil
+	}
+
+	// Review dates follow the timezone of the hotel they move to.
+	var target models.Hotel
+	if err := tx.First(&target, hotelID).Error; err != nil {
+		return err
+	}
+	var sources []struct {
+		Timezone  string
+		ListingID uint
+	}
+	if err := tx.Table("hotel_platform_listings l").
+		Select("h.timezone, l.id AS listing_id").
+		Joins("JOIN hotels h ON h.id = l.hotel_id").
+		Where("l.id IN ? AND h.timezone <> ?", listingIDs, target.Timezone).
+		Scan(&sources).Error; err != nil {
+		return err
+	}
+	byTimezone := make(map[string][]uint)
+	for _, src := range sources {
+		byTimezone[src.Timezone] = append(byTimezone[src.Timezone], src.ListingID)
+	}
+	for tz, ids := range byTimezone {
+		if err := models.ShiftReviewTimezone(tx, tz, target.Timezone, "listing_id IN ?", ids); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Model(&models.HotelPlatformListing{}).Where("id IN ?", listingIDs).
+		Update("hotel_id", hotelID).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&models.Review{}).Where("listing_id IN ?", listingIDs).
+		Update("hotel_id", hotelID).Error; err != nil {
+		return err
+	}
+	return publishMovedReviews(tx, listingIDs)
+}
+
+// publishMovedReviews enqueues an update event for each visible review of
+// the listings, so consumers of the events topic see their new hotel and
+// local date. Hidden reviews were published as deleted and stay that way.
+func publishMovedReviews(tx *gorm.DB, listingIDs []uint) error {
+	var listings []models.HotelPlatformListing
+	if err := tx.Where("id IN ?", listingIDs).Find(&listings).Error; err != nil {
+		return err
+	}
+	byID := make(map[uint]models.HotelPlatformListing, len(listings))
+	for _, l := range listings {
+		byID[l.ID] = l
+	}
+	var platforms []models.Platform
+	if err := tx.Find(&platforms).Error; err != nil {
+		return err
+	}
+	platformByID := make(map[uint]models.Platform, len(platforms))
+	for _, p := range platforms {
+		platformByID[p.ID] = p
+	}
+
+	var batch []models.Review
+	return tx.Where("listing_id IN ? AND moderation_status = ?", listingIDs, models.ModerationVisible).
+		FindInBatches(&batch, 1000, func(btx *gorm.DB, _ int) error {
+			for _, r := range batch {
+				if err := models.EnqueueReviewEvent(btx, "update", r, byID[r.ListingID], platformByID[r.PlatformID]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
+// recomputeHotels rebuilds the summaries and suspicion scores of hotels that
+// reviews moved between. Patterns such as a burst or a template are per
+// hotel, so a moved review stops counting towards its old hotel's and may
+// complete one at its new hotel.
+func recomputeHotels(tx *gorm.DB, hotelIDs ...uint) error {
+	if err := models.RecomputeHotelSummaries(tx, hotelIDs...); err != nil {
+		return err
+	}
+	return models.RescoreHotels(tx, hotelIDs...)
+}
+
+// deleteIfEmpty removes a hotel, its summaries and proposals pointing at it
+// once no listing refers to it any more.
+func deleteIfEmpty(tx *gorm.DB, hotelID uint) error {
+	var remaining int64
+	if err := tx.Model(&models.HotelPlatformListing{}).Where("hotel_id = ?", hotelID).Count(&remaining).Error; err != nil {
+		return err
+	}
+	if remaining > 0 {
+		return nil
+	}
+	if err := tx.Where("hotel_id = ? AND status = ?", hotelID, models.MatchProposed).
+		Delete(&models.HotelMatchCandidate{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("hotel_id = ?", hotelID).Delete(&models.HotelRatingsSummary{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("hotel_id = ?", hotelID).Delete(&models.HotelAspectSummary{}).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&models.Hotel{}, hotelID).Error
+}
+
+// SetTimezone sets a hotel's IANA timezone, or clears it when timezone is
+// empty, and moves its review dates to it.
+func SetTimezone(db *gorm.DB, hotelID uint, timezone string) (models.Hotel, error) {
+	var hotel models.Hotel
+	if timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil || timezone == "Local" {
+			return hotel, fmt.Errorf("unknown timezone %q: %w", timezone, ErrInvalid)
+		}
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&hotel, hotelID).Error; err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("hotel %d: %w", hotelID, ErrNotFound)
+		} else if err != nil {
+			return err
+		}
+		if hotel.Timezone == timezone {
+			return nil
+		}
+		if err := models.ShiftReviewTimezone(tx, hotel.Timezone, timezone, "hotel_id = ?", hotelID); err != nil {
+			return err
+		}
+		hotel.Timezone = timezone
+		return tx.Model(&hotel).Update("timezone", timezone).Error
+	})
+	return hotel, err
+}
@@ -0,0 +1,86 @@
+package hotels
+
+import (
+	"math"
+	"strings"
+
+	"review-system/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MatchThreshold is the lowest score at which a match is proposed. A name
+// match alone reaches it; address and distance move the score up or down.
+const MatchThreshold = 0.6
+
+// ProposeMatches records a HotelMatchCandidate for every other canonical
+// hotel that looks like the same property as the listing.
+func ProposeMatches(db *gorm.DB, listing models.HotelPlatformListing) error {
+	normalized := models.NormalizeHotelName(listing.Name)
+	if normalized == "" {
+		return nil
+	}
+
+	var candidates []models.Hotel
+	if err := db.Where("normalized_name = ? AND id <> ?", normalized, listing.HotelID).Find(&candidates).Error; err != nil {
+		return err
+	}
+
+	for _, hotel := range candidates {
+		score, reasons := scoreMatch(listing, hotel)
+		if score < MatchThreshold {
+			continue
+		}
+		proposal := models.HotelMatchCandidate{
+			ListingID: listing.ID,
+			HotelID:   hotel.ID,
+			Score:     score,
+			Reason:    strings.Join(reasons, ","),
+			Status:    models.MatchProposed,
+		}
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&proposal).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scoreMatch rates how likely a listing and a hotel with the same normalized
+// name are the same property. Coordinates far apart rule a match out, since
+// chains reuse names across cities.
+func scoreMatch(listing models.HotelPlatformListing, hotel models.Hotel) (float64, []string) {
+	score, reasons := 0.6, []string{"name"}
+
+	if a, b := normalizeAddress(listing.Address), normalizeAddress(hotel.Address); a != "" && a == b {
+		score += 0.2
+		reasons = append(reasons, "address")
+	}
+
+	if listing.Latitude != nil && listing.Longitude != nil && hotel.Latitude != nil && hotel.Longitude != nil {
+		d := distanceMeters(*listing.Latitude, *listing.Longitude, *hotel.Latitude, *hotel.Longitude)
+		switch {
+		case d <= 150:
+			score += 0.3
+			reasons = append(reasons, "geo")
+		case d > 2000:
+			return 0, nil
+		}
+	}
+	return math.Min(score, 1), reasons
+}
+
+func normalizeAddress(addr string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.NewReplacer(",", " ", ".", " ").Replace(addr))), " ")
+}
+
+// distanceMeters is the haversine distance between two coordinates.
+func distanceMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadius = 6371000.0
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
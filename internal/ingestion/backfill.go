@@ -0,0 +1,51 @@
+package ingestion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"review-system/internal/storage"
+)
+
+// Backfill produces every daily file in the store dated between from and to
+// (inclusive) that has not been processed yet. A failing day is logged and
+// skipped so one bad file does not hold up the rest; the failures are
+// returned together at the end.
+func Backfill(ctx context.Context, store storage.ObjectStore, from, to time.Time) (int, error) {
+	objects, err := store.List(ctx, S3Prefix)
+	if err != nil {
+		return 0, err
+	}
+
+	var days []string
+	for _, obj := range objects {
+		name := path.Base(obj.Key)
+		if path.Dir(obj.Key) != path.Clean(path.Join(".", S3Prefix)) || !strings.HasSuffix(name, ".jl") {
+			continue
+		}
+		day, err := time.Parse("2006-01-02", strings.TrimSuffix(name, ".jl"))
+		if err != nil || day.Before(from) || day.After(to) {
+			continue
+		}
+		days = append(days, day.Format("2006-01-02"))
+	}
+	sort.Strings(days)
+
+	produced := 0
+	var errs []error
+	for _, day := range days {
+		if err := IngestDailyFile(ctx, store, day); err != nil {
+			log.Printf("❌ Backfill of %s failed: %v", day, err)
+			errs = append(errs, fmt.Errorf("%s: %w", day, err))
+			continue
+		}
+		produced++
+	}
+	return produced, errors.Join(errs...)
+}
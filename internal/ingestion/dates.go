@@ -0,0 +1,143 @@
+package ingestion
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layouts carrying a UTC offset or zone, tried in order.
+var zonedDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999-0700",
+	"2006-01-02 15:04:05.999999999-07:00",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// Layouts without an offset. They are wall-clock times at the hotel.
+var floatingDateLayouts = []string{
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+	"January 2, 2006", // Hotels.com
+	"Jan 2, 2006",
+	"2 January 2006", // Booking.com
+	"2 Jan 2006",
+}
+
+// agodaDatePattern matches the .NET JSON dates older Agoda exports use, e.g.
+// "/Date(1713484800000+0700)/".
+var agodaDatePattern = regexp.MustCompile(`^/Date\((-?\d+)([+-]\d{4})?\)/$`)
+
+// reviewDate is a date as a provider sent it.
+type reviewDate struct {
+	Raw string
+	// At is the instant. For floating values it is the wall clock read as
+	// UTC until in resolves it against the hotel's timezone.
+	At time.Time
+	// Offset is the UTC offset in seconds the value carried, nil if none.
+	Offset *int
+	// Floating is set for values without an offset, such as "2025-04-19".
+	Floating bool
+}
+
+// parseReviewDate reads a date sent as RFC 3339, a date without a time, one
+// of the provider-specific layouts, or a Unix timestamp in seconds or
+// milliseconds. A missing value returns the zero reviewDate and no error;
+// anything unrecognized is an error.
+func parseReviewDate(val interface{}) (reviewDate, error) {
+	switch v := val.(type) {
+	case nil:
+		return reviewDate{}, nil
+	case float64:
+		return unixReviewDate(int64(v), strconv.FormatFloat(v, 'f', -1, 64)), nil
+	case string:
+		return parseReviewDateString(strings.TrimSpace(v))
+	default:
+		return reviewDate{Raw: getStr(v)}, fmt.Errorf("unexpected date type %T", val)
+	}
+}
+
+func parseReviewDateString(s string) (reviewDate, error) {
+	d := reviewDate{Raw: s}
+	if s == "" {
+		return d, nil
+	}
+
+	for _, layout := range zonedDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			_, offset := t.Zone()
+			d.At, d.Offset = t, &offset
+			return d, nil
+		}
+	}
+	for _, layout := range floatingDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			d.At, d.Floating = t, true
+			return d, nil
+		}
+	}
+
+	if m := agodaDatePattern.FindStringSubmatch(s); m != nil {
+		ms, _ := strconv.ParseInt(m[1], 10, 64)
+		t := time.UnixMilli(ms).UTC()
+		if m[2] != "" {
+			zone, _ := time.Parse("-0700", m[2])
+			_, offset := zone.Zone()
+			d.At, d.Offset = t.In(time.FixedZone("", offset)), &offset
+		} else {
+			utc := 0
+			d.At, d.Offset = t, &utc
+		}
+		return d, nil
+	}
+
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return unixReviewDate(n, s), nil
+	}
+	return d, fmt.Errorf("unrecognized date %q", s)
+}
+
+// unixReviewDate reads seconds or, for values too large to be seconds,
+// milliseconds since the epoch. Timestamps are absolute, so they count as
+// sent with a zero offset.
+func unixReviewDate(n int64, raw string) reviewDate {
+	t := time.Unix(n, 0)
+	if n > 1e11 {
+		t = time.UnixMilli(n)
+	}
+	utc := 0
+	return reviewDate{Raw: raw, At: t.UTC(), Offset: &utc}
+}
+
+// in returns the instant and the calendar date at the hotel. Floating values
+// are wall-clock times in loc. The local date is taken in loc when the
+// hotel's timezone is known, otherwise in the offset the provider sent, and
+// otherwise in UTC. Both are nil for a missing date.
+func (d reviewDate) in(loc *time.Location) (*time.Time, *time.Time) {
+	if d.At.IsZero() {
+		return nil, nil
+	}
+
+	at := d.At
+	if d.Floating && loc != nil {
+		at = time.Date(at.Year(), at.Month(), at.Day(), at.Hour(), at.Minute(), at.Second(), at.Nanosecond(), loc)
+	}
+
+	var local time.Time
+	switch {
+	case d.Floating:
+		local = d.At
+	case loc != nil:
+		local = at.In(loc)
+	case d.Offset != nil:
+		local = at.In(time.FixedZone("", *d.Offset))
+	default:
+		local = at.UTC()
+	}
+	date := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	return &at, &date
+}
@@ -0,0 +1,129 @@
+package ingestion
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseReviewDate(t *testing.T) {
+	offset := func(seconds int) *int { return &seconds }
+	tests := []struct {
+		name     string
+		val      interface{}
+		at       string // RFC 3339 in UTC, "" for none
+		offset   *int
+		floating bool
+	}{
+		// With an offset or zone.
+		{"rfc3339 offset", "2025-04-19T22:30:00+07:00", "2025-04-19T15:30:00Z", offset(7 * 3600), false},
+		{"rfc3339 utc", "2025-04-19T20:00:00Z", "2025-04-19T20:00:00Z", offset(0), false},
+		{"rfc3339 fraction", "2025-04-19T20:00:00.250-05:00", "2025-04-20T01:00:00.25Z", offset(-5 * 3600), false},
+		{"offset without colon", "2025-04-19T20:00:00-0500", "2025-04-20T01:00:00Z", offset(-5 * 3600), false},
+		{"space separated offset", "2025-04-19 08:00:00+07:00", "2025-04-19T01:00:00Z", offset(7 * 3600), false},
+		{"rfc1123z", "Sat, 19 Apr 2025 20:00:00 +0700", "2025-04-19T13:00:00Z", offset(7 * 3600), false},
+		{"agoda with offset", "/Date(1713484800000+0700)/", "2024-04-19T00:00:00Z", offset(7 * 3600), false},
+		{"agoda without offset", "/Date(1713484800000)/", "2024-04-19T00:00:00Z", offset(0), false},
+		{"unix seconds", float64(1713484800), "2024-04-19T00:00:00Z", offset(0), false},
+		{"unix millis", float64(1713484800123), "2024-04-19T00:00:00.123Z", offset(0), false},
+		{"unix seconds string", "1713484800", "2024-04-19T00:00:00Z", offset(0), false},
+
+		// Without an offset.
+		{"date only", "2025-04-19", "2025-04-19T00:00:00Z", nil, true},
+		{"date and time", "2025-04-19T23:30:00", "2025-04-19T23:30:00Z", nil, true},
+		{"space separated", "2025-04-19 23:30:00", "2025-04-19T23:30:00Z", nil, true},
+		{"hotels.com", "April 19, 2025", "2025-04-19T00:00:00Z", nil, true},
+		{"short month", "Apr 19, 2025", "2025-04-19T00:00:00Z", nil, true},
+		{"booking.com", "19 April 2025", "2025-04-19T00:00:00Z", nil, true},
+		{"booking.com short", " 19 Apr 2025 ", "2025-04-19T00:00:00Z", nil, true},
+
+		// Missing.
+		{"nil", nil, "", nil, false},
+		{"empty", "", "", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := parseReviewDate(tt.val)
+			if err != nil {
+				t.Fatalf("parseReviewDate(%v): %v", tt.val, err)
+			}
+			at := ""
+			if !d.At.IsZero() {
+				at = d.At.UTC().Format(time.RFC3339Nano)
+			}
+			if at != tt.at {
+				t.Errorf("At = %s, want %s", at, tt.at)
+			}
+			if (d.Offset == nil) != (tt.offset == nil) || d.Offset != nil && *d.Offset != *tt.offset {
+				t.Errorf("Offset = %v, want %v", deref(d.Offset), deref(tt.offset))
+			}
+			if d.Floating != tt.floating {
+				t.Errorf("Floating = %v, want %v", d.Floating, tt.floating)
+			}
+		})
+	}
+}
+
+func TestParseReviewDateInvalid(t *testing.T) {
+	for _, val := range []interface{}{"yesterday", "19/04/2025", "2025-13-01", "/Date(abc)/", true} {
+		d, err := parseReviewDate(val)
+		if err == nil {
+			t.Errorf("parseReviewDate(%v) = %v, want an error", val, d.At)
+		}
+		if !d.At.IsZero() {
+			t.Errorf("parseReviewDate(%v) set At = %v", val, d.At)
+		}
+	}
+	if d, _ := parseReviewDate("yesterday"); d.Raw != "yesterday" {
+		t.Errorf("Raw = %q, want the value as sent", d.Raw)
+	}
+}
+
+func TestReviewDateIn(t *testing.T) {
+	ict := time.FixedZone("ICT", 7*3600)
+	tests := []struct {
+		name  string
+		val   string
+		loc   *time.Location
+		at    string // RFC 3339 in UTC
+		local string // YYYY-MM-DD
+	}{
+		// The hotel's timezone decides the local date when it is known.
+		{"offset, hotel zone", "2025-04-19T20:00:00Z", ict, "2025-04-19T20:00:00Z", "2025-04-20"},
+		// Otherwise the offset the provider sent does.
+		{"offset, no hotel zone", "2025-04-19T22:30:00+07:00", nil, "2025-04-19T15:30:00Z", "2025-04-19"},
+		{"negative offset, no hotel zone", "2025-04-19T20:00:00-0500", nil, "2025-04-20T01:00:00Z", "2025-04-19"},
+		// Floating values are wall clock at the hotel.
+		{"floating, hotel zone", "2025-04-19T06:00:00", ict, "2025-04-18T23:00:00Z", "2025-04-19"},
+		{"floating date, hotel zone", "2025-04-19", ict, "2025-04-18T17:00:00Z", "2025-04-19"},
+		{"floating, no hotel zone", "2025-04-19T23:30:00", nil, "2025-04-19T23:30:00Z", "2025-04-19"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := parseReviewDate(tt.val)
+			if err != nil {
+				t.Fatal(err)
+			}
+			at, local := d.in(tt.loc)
+			if at == nil || local == nil {
+				t.Fatal("in returned nil for a date")
+			}
+			if got := at.UTC().Format(time.RFC3339); got != tt.at {
+				t.Errorf("at = %s, want %s", got, tt.at)
+			}
+			if got := local.Format("2006-01-02"); got != tt.local {
+				t.Errorf("local date = %s, want %s", got, tt.local)
+			}
+		})
+	}
+
+	if at, local := (reviewDate{}).in(ict); at != nil || local != nil {
+		t.Errorf("missing date = %v, %v, want nil", at, local)
+	}
+}
+
+func deref(p *int) interface{} {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
@@ -0,0 +1,124 @@
+package ingestion
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"review-system/models"
+
+	"github.com/segmentio/kafka-go"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	KafkaEventsTopic   = models.ReviewEventsTopic
+	OutboxPollInterval = getEnvDuration("OUTBOX_POLL_INTERVAL", time.Second)
+	OutboxBatchSize    = 100
+)
+
+// outboxLockKey is the Postgres advisory lock held by the active relay. Only
+// one relay publishes at a time, which keeps events for a hotel in order even
+// when several instances of the service are running.
+const outboxLockKey = 7_270_001
+
+// StartOutboxRelay publishes outbox rows to Kafka in the background. Delivery
+// is at-least-once: a row is only marked published after the broker acks it,
+// so a crash in between republishes it. Messages are keyed by hotel ID and
+// hashed to a partition, so each hotel's events stay in order.
+func StartOutboxRelay() {
+	go func() {
+		writer := &kafka.Writer{
+			Addr:         kafka.TCP(KafkaBrokers...),
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			MaxAttempts:  5,
+		}
+		defer writer.Close()
+
+		ticker := time.NewTicker(OutboxPollInterval)
+		defer ticker.Stop()
+
+		log.Printf("🚀 Outbox relay started, publishing to %s", KafkaEventsTopic)
+
+		for range ticker.C {
+			for {
+				n, err := relayOutboxBatch(context.Background(), models.GetDB(), writer)
+				if err != nil {
+					log.Printf("⚠️  Outbox relay: %v", err)
+					break
+				}
+				if n < OutboxBatchSize {
+					break
+				}
+			}
+		}
+	}()
+}
+
+// relayOutboxBatch publishes up to OutboxBatchSize pending rows and returns
+// how many it published. Nothing is published if another relay holds the lock.
+func relayOutboxBatch(ctx context.Context, db *gorm.DB, writer *kafka.Writer) (int, error) {
+	published := 0
+	var failed []uint64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", outboxLockKey).Scan(&locked).Error; err != nil {
+			return fmt.Errorf("failed to take relay lock: %w", err)
+		}
+		if !locked {
+			return nil
+		}
+
+		var events []models.OutboxEvent
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("published_at IS NULL").
+			Order("id").
+			Limit(OutboxBatchSize).
+			Find(&events).Error; err != nil {
+			return fmt.Errorf("failed to load outbox: %w", err)
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		ids := make([]uint64, len(events))
+		msgs := make([]kafka.Message, len(events))
+		for i, e := range events {
+			ids[i] = e.ID
+			msgs[i] = kafka.Message{
+				Topic: e.Topic,
+				Key:   []byte(e.PartitionKey),
+				Value: []byte(e.Payload),
+				Headers: []kafka.Header{
+					{Key: "event-id", Value: []byte(strconv.FormatUint(e.ID, 10))},
+					{Key: "event-type", Value: []byte(e.EventType)},
+				},
+			}
+		}
+
+		if err := writer.WriteMessages(ctx, msgs...); err != nil {
+			failed = ids
+			return fmt.Errorf("failed to publish %d events: %w", len(msgs), err)
+		}
+
+		now := time.Now()
+		if err := tx.Model(&models.OutboxEvent{}).Where("id IN ?", ids).
+			Update("published_at", now).Error; err != nil {
+			return fmt.Errorf("failed to mark events published: %w", err)
+		}
+		published = len(events)
+		return nil
+	})
+
+	// Record the failed attempt once the rolled-back transaction has released
+	// its row locks.
+	if len(failed) > 0 {
+		db.Model(&models.OutboxEvent{}).Where("id IN ?", failed).
+			UpdateColumn("attempts", gorm.Expr("attempts + 1"))
+	}
+	return published, err
+}
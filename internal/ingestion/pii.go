@@ -0,0 +1,191 @@
+package ingestion
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"review-system/internal/pii"
+	"review-system/models"
+
+	"gorm.io/gorm"
+)
+
+// Personal data is redacted from review titles and text, and from management
+// replies, before they are stored. PII_ORIGINAL_POLICY decides whether the original is dropped or
+// kept encrypted with PII_ENCRYPTION_KEY, and PII_PATTERNS adds patterns to
+// the built-in detectors, see pii.NewRedactor.
+var (
+	PIIRedactor = newPIIRedactor(getEnv("PII_PATTERNS", ""))
+	PIIVault    = newPIIVault(getEnv("PII_ORIGINAL_POLICY", pii.PolicyDrop), getEnv("PII_ENCRYPTION_KEY", ""))
+)
+
+// ErrOriginalNotKept is returned for a redacted review whose original text
+// was dropped.
+var ErrOriginalNotKept = errors.New("original text was not kept")
+
+func newPIIRedactor(spec string) *pii.Redactor {
+	r, err := pii.NewRedactor(spec)
+	if err != nil {
+		log.Printf("⚠️  Invalid PII_PATTERNS, using the built-in detectors: %v", err)
+		r, _ = pii.NewRedactor("")
+	}
+	return r
+}
+
+// newPIIVault returns nil unless originals are to be kept. A bad key drops
+// originals rather than storing them in the clear.
+func newPIIVault(policy, key string) *pii.Vault {
+	switch strings.ToLower(policy) {
+	case pii.PolicyDrop, "":
+		return nil
+	case pii.PolicyEncrypt:
+		v, err := pii.NewVault(key)
+		if err != nil {
+			log.Printf("⚠️  Invalid PII_ENCRYPTION_KEY, original review text will not be kept: %v", err)
+			return nil
+		}
+		return v
+	default:
+		log.Printf("⚠️  Unknown PII_ORIGINAL_POLICY %q, original review text will not be kept", policy)
+		return nil
+	}
+}
+
+// originalText is what is sealed into Review.OriginalEncrypted.
+type originalText struct {
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+// RedactReview replaces personal data in the review's title and text, records
+// what was found in PIIKinds and, when the policy keeps originals, seals the
+// original into OriginalEncrypted.
+func RedactReview(review *models.Review) error {
+	title, titleKinds := PIIRedactor.Redact(review.ReviewTitle)
+	text, textKinds := PIIRedactor.Redact(review.ReviewText)
+	kinds := mergeKinds(titleKinds, textKinds)
+
+	joined := strings.Join(kinds, ",")
+	review.PIIKinds = &joined
+	review.OriginalEncrypted = nil
+	if len(kinds) > 0 && PIIVault != nil {
+		plain, err := json.Marshal(originalText{Title: review.ReviewTitle, Text: review.ReviewText})
+		if err != nil {
+			return err
+		}
+		if review.OriginalEncrypted, err = PIIVault.Seal(plain); err != nil {
+			return fmt.Errorf("failed to encrypt original text: %w", err)
+		}
+	}
+	review.ReviewTitle, review.ReviewText = title, text
+	return nil
+}
+
+// RedactResponse replaces personal data in a management reply, which often
+// quotes the guest's name, booking or room, and records what was found in
+// PIIKinds. The original reply is not kept.
+func RedactResponse(resp *models.ManagementResponse) {
+	text, kinds := PIIRedactor.Redact(resp.Text)
+	joined := strings.Join(kinds, ",")
+	resp.Text, resp.PIIKinds = text, &joined
+}
+
+// OriginalText returns a review's title and text as received. Reviews without
+// personal data are returned as stored.
+func OriginalText(review models.Review) (string, string, error) {
+	if review.PIIKinds == nil || *review.PIIKinds == "" {
+		return review.ReviewTitle, review.ReviewText, nil
+	}
+	if review.OriginalEncrypted == nil {
+		return "", "", ErrOriginalNotKept
+	}
+	if PIIVault == nil {
+		return "", "", errors.New("no PII_ENCRYPTION_KEY to decrypt the original text")
+	}
+	plain, err := PIIVault.Open(review.OriginalEncrypted)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to decrypt original text: %w", err)
+	}
+	var orig originalText
+	if err := json.Unmarshal(plain, &orig); err != nil {
+		return "", "", err
+	}
+	return orig.Title, orig.Text, nil
+}
+
+// RedactExistingReviews redacts reviews stored before redaction ran during
+// ingestion, which are the ones with a NULL pii_kinds. Their aspect mentions
+// quote the text, so they are extracted again.
+func RedactExistingReviews(db *gorm.DB) (int, error) {
+	redacted := 0
+	var batch []models.Review
+	err := db.Where("pii_kinds IS NULL").FindInBatches(&batch, 1000, func(tx *gorm.DB, _ int) error {
+		for _, r := range batch {
+			if err := RedactReview(&r); err != nil {
+				return err
+			}
+			err := db.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Model(&models.Review{}).Where("id = ?", r.ID).Updates(map[string]interface{}{
+					"review_title":       r.ReviewTitle,
+					"review_text":        r.ReviewText,
+					"pii_kinds":          r.PIIKinds,
+					"original_encrypted": r.OriginalEncrypted,
+				}).Error; err != nil {
+					return err
+				}
+				if *r.PIIKinds == "" {
+					return nil
+				}
+				return models.SaveAspectMentions(tx, r)
+			})
+			if err != nil {
+				return fmt.Errorf("failed to redact review (id=%d): %w", r.ID, err)
+			}
+			if *r.PIIKinds != "" {
+				redacted++
+			}
+		}
+		return nil
+	}).Error
+	return redacted, err
+}
+
+// RedactExistingResponses redacts management replies stored before redaction
+// ran on them, which are the ones with a NULL pii_kinds.
+func RedactExistingResponses(db *gorm.DB) (int, error) {
+	redacted := 0
+	var batch []models.ManagementResponse
+	err := db.Where("pii_kinds IS NULL").FindInBatches(&batch, 1000, func(tx *gorm.DB, _ int) error {
+		for _, r := range batch {
+			RedactResponse(&r)
+			if err := db.Model(&models.ManagementResponse{}).Where("id = ?", r.ID).Updates(map[string]interface{}{
+				"text":      r.Text,
+				"pii_kinds": r.PIIKinds,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to redact response (id=%d): %w", r.ID, err)
+			}
+			if *r.PIIKinds != "" {
+				redacted++
+			}
+		}
+		return nil
+	}).Error
+	return redacted, err
+}
+
+func mergeKinds(a, b []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, k := range append(a, b...) {
+		if !seen[k] {
+			seen[k] = true
+			out = append(out, k)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -1,13 +1,46 @@
 package ingestion
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"log"
+	"math"
+	"review-system/internal/hotels"
+	"review-system/internal/langdetect"
+	"review-system/internal/quality"
 	"review-system/models"
+	"strconv"
 	"strings"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WriteMode controls how the sink treats a review that is already stored.
+type WriteMode int
+
+const (
+	// InsertOnly skips reviews whose hotelReviewId already exists. The live
+	// consumer runs in this mode.
+	InsertOnly WriteMode = iota
+	// Upsert overwrites the stored review with the incoming record. Replays
+	// run in this mode so a parsing fix reaches rows that were already written.
+	Upsert
+)
+
+// Outcome reports what the sink did with a single record.
+type Outcome int
+
+const (
+	OutcomeSkipped Outcome = iota
+	OutcomeInserted
+	OutcomeUpdated
+	OutcomeUnchanged
+	OutcomeDeleted
+	OutcomeRejected    // failed a data quality rule and was dropped
+	OutcomeQuarantined // failed a data quality rule and was sent to the DLQ
 )
 
 func ProcessJLLineWithSuppressedErrors(raw map[string]interface{}, db *gorm.DB) {
@@ -34,12 +67,28 @@ func safeWrapJLLine(raw map[string]interface{}, db *gorm.DB) error {
 }
 
 func ProcessJLLine(raw map[string]interface{}, db *gorm.DB) {
+	if _, err := writeReview(raw, db, InsertOnly); err != nil {
+		log.Printf("❌ %v", err)
+	}
+}
+
+// UpsertJLLine writes a single record with upsert semantics and reports what
+// happened to it, for callers that need to count outcomes.
+func UpsertJLLine(raw map[string]interface{}, db *gorm.DB) (Outcome, error) {
+	return writeReview(raw, db, Upsert)
+}
+
+func writeReview(raw map[string]interface{}, db *gorm.DB, mode WriteMode) (outcome Outcome, err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			log.Printf("Recovered from panic in ingestion: %v", r)
+			outcome, err = OutcomeSkipped, fmt.Errorf("malformed record: %v", r)
 		}
 	}()
 
+	if getStr(raw["op"]) == "delete" {
+		return deleteReview(raw, db)
+	}
+
 	hotelID := int(raw["hotelId"].(float64))
 	platformName := raw["platform"].(string)
 	hotelName := raw["hotelName"].(string)
@@ -47,102 +96,371 @@ func ProcessJLLine(raw map[string]interface{}, db *gorm.DB) {
 	comment := raw["comment"].(map[string]interface{})
 	reviewerInfo := comment["reviewerInfo"].(map[string]interface{})
 
-	// ✅ Ensure hotel creation is concurrency-safe
-	var hotel models.Hotel
-	if err := db.Where("external_id = ?", hotelID).First(&hotel).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			newHotel := models.Hotel{ExternalID: hotelID, Name: hotelName}
-			if err := db.Create(&newHotel).Error; err != nil {
-				// Retry after create error (duplicate from another thread)
-				if dbErr := db.Where("external_id = ?", hotelID).First(&hotel).Error; dbErr != nil {
-					log.Printf("❌ Hotel fetch failed after duplicate insert (hotelId=%d): %v", hotelID, dbErr)
-					return
-				}
-			} else {
-				hotel = newHotel
-			}
-		} else {
-			log.Printf("❌ DB error querying hotel (hotelId=%d): %v", hotelID, err)
-			return
-		}
-	}
+	hotelReviewID, idErr := parseInt64(comment["hotelReviewId"])
+	rating := float32(comment["rating"].(float64))
+	title := getStr(comment["reviewTitle"])
+	text := getStr(comment["reviewComments"])
+	reviewDate, dateErr := parseReviewDate(comment["reviewDate"])
 
 	// ✅ Platform creation (also concurrency-safe)
 	var platform models.Platform
 	if err := db.Where("name = ?", platformName).First(&platform).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			platform = models.Platform{Name: platformName}
+			platform = models.Platform{Name: platformName}.WithDefaultScale()
 			if err := db.Create(&platform).Error; err != nil {
 				if err := db.Where("name = ?", platformName).First(&platform).Error; err != nil {
-					log.Printf("❌ Platform recovery failed: %v", err)
-					return
+					return OutcomeSkipped, fmt.Errorf("platform recovery failed: %w", err)
 				}
 			}
 		} else {
-			log.Printf("❌ DB error querying platform: %v", err)
-			return
+			return OutcomeSkipped, fmt.Errorf("DB error querying platform: %w", err)
 		}
 	}
 
-	// ✅ Reviewer creation (based on composite key)
-	reviewer := models.Reviewer{
-		CountryName:     getStr(reviewerInfo["countryName"]),
-		ReviewGroupName: getStr(reviewerInfo["reviewGroupName"]),
-		RoomTypeName:    getStr(reviewerInfo["roomTypeName"]),
+	// ✅ Data quality rules
+	check := QualityEngine.Evaluate(quality.Record{
+		Platform:         platform.Name,
+		HotelReviewID:    hotelReviewID,
+		HotelReviewIDErr: idErr,
+		Rating:           rating,
+		ScaleMin:         platform.ScaleMin,
+		ScaleMax:         platform.ScaleMax,
+		ReviewDate:       reviewDate.At,
+		ReviewDateErr:    dateErr,
+		Title:            title,
+		Text:             text,
+	})
+	if len(check.Violations) > 0 {
+		if err := quality.RecordViolations(db, platform.Name, check); err != nil {
+			log.Printf("⚠️  Failed to count quality violations: %v", err)
+		}
 	}
-	if err := db.Where(&reviewer).FirstOrCreate(&reviewer).Error; err != nil {
-		log.Printf("❌ Error creating reviewer: %v", err)
-		return
+	switch check.Action {
+	case quality.Reject:
+		log.Printf("🚫 Rejected review (hotelReviewId=%v): %s", comment["hotelReviewId"], strings.Join(check.Flags(), ","))
+		return OutcomeRejected, nil
+	case quality.Quarantine:
+		if err := quarantine(raw, check); err != nil {
+			return OutcomeSkipped, fmt.Errorf("failed to quarantine review (hotelReviewId=%d): %w", hotelReviewID, err)
+		}
+		log.Printf("🧪 Quarantined review (hotelReviewId=%d): %s", hotelReviewID, strings.Join(check.Flags(), ","))
+		return OutcomeQuarantined, nil
+	}
+
+	// ✅ Resolve the provider's hotel to a canonical hotel (concurrency-safe)
+	listing, hotel, err := hotels.ResolveListing(db, hotels.ListingInput{
+		PlatformID: platform.ID,
+		ExternalID: hotelID,
+		Name:       hotelName,
+		Address:    getStr(raw["hotelAddress"]),
+		Latitude:   getFloat(raw["latitude"]),
+		Longitude:  getFloat(raw["longitude"]),
+	})
+	if err != nil {
+		return OutcomeSkipped, err
 	}
 
-	// ✅ Review creation (skip if duplicate)
-	hotelReviewID := parseInt64(comment["hotelReviewId"])
-	var existing models.Review
-	if err := db.Where("hotel_review_id = ?", hotelReviewID).First(&existing).Error; err == nil {
-		return // duplicate
-	} else if err != gorm.ErrRecordNotFound {
-		log.Printf("❌ Error checking for review (id=%d): %v", hotelReviewID, err)
-		return
+	// ✅ Reviewer dimensions (concurrency-safe)
+	info := parseReviewerInfo(reviewerInfo)
+	dims, err := models.ResolveDimensions(db, platform.ID, listing.ID, info)
+	if err != nil {
+		return OutcomeSkipped, err
 	}
 
+	reviewedAt, localDate := reviewDate.in(hotel.Location())
+	language, _ := langdetect.Detect(title + "\n" + text)
 	review := models.Review{
-		HotelID:       hotel.ID,
-		PlatformID:    platform.ID,
-		ReviewerID:    reviewer.ID,
-		HotelReviewID: hotelReviewID,
-		Rating:        float32(comment["rating"].(float64)),
-		ReviewTitle:   getStr(comment["reviewTitle"]),
-		ReviewText:    getStr(comment["reviewComments"]),
-		ReviewDate:    parseTime(getStr(comment["reviewDate"])),
+		HotelID:          hotel.ID,
+		PlatformID:       platform.ID,
+		ListingID:        listing.ID,
+		HotelReviewID:    hotelReviewID,
+		Rating:           rating,
+		ReviewTitle:      title,
+		ReviewText:       text,
+		ReviewDate:       reviewedAt,
+		ReviewDateRaw:    reviewDate.Raw,
+		ReviewDateOffset: reviewDate.Offset,
+		ReviewLocalDate:  localDate,
+		Language:         language,
+		QualityFlags:     strings.Join(check.Flags(), ","),
+		ModerationStatus: models.ModerationVisible,
+
+		CountryID:         dims.CountryID,
+		TravelerTypeID:    dims.TravelerTypeID,
+		RoomTypeID:        dims.RoomTypeID,
+		ReviewerProfileID: dims.ReviewerProfileID,
+		StayDate:          info.StayDate,
+		LengthOfStay:      info.LengthOfStay,
+	}
+	review.NormalizedRating = platform.Normalize(review.Rating)
+	if err := RedactReview(&review); err != nil {
+		return OutcomeSkipped, fmt.Errorf("failed to redact review (hotelReviewId=%d): %w", hotelReviewID, err)
 	}
+	review.ScoreSentiment()
+	review.SignReview()
+	subRatings := parseSubRatings(comment, platform)
+	response := parseResponse(comment)
 
-	if err := db.Create(&review).Error; err != nil {
-		log.Printf("❌ Failed to insert review (hotelReviewId=%d): %v", hotelReviewID, err)
-		return
+	// ✅ Review, summary and outbox event are written in one transaction
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var existing models.Review
+		if err := tx.Where("hotel_review_id = ?", review.HotelReviewID).First(&existing).Error; err == nil {
+			if mode == InsertOnly {
+				outcome = OutcomeSkipped
+				return nil
+			}
+			outcome, err = updateReview(tx, existing, review, subRatings)
+			if err != nil {
+				return err
+			}
+			if err := savePlatformResponse(tx, existing.ID, response); err != nil {
+				return err
+			}
+			if outcome != OutcomeUpdated {
+				return nil
+			}
+			review.ID = existing.ID
+			if err := models.SaveAspectMentions(tx, review); err != nil {
+				return fmt.Errorf("failed to save aspect mentions (hotelReviewId=%d): %w", review.HotelReviewID, err)
+			}
+			// Consumers were told a hidden review is gone; it is published
+			// again when a moderator makes it visible.
+			if !existing.Visible() {
+				return nil
+			}
+			return models.EnqueueReviewEvent(tx, "update", review, listing, platform)
+		} else if err != gorm.ErrRecordNotFound {
+			return fmt.Errorf("error checking for review (id=%d): %w", review.HotelReviewID, err)
+		}
+
+		if err := tx.Create(&review).Error; err != nil {
+			return fmt.Errorf("failed to insert review (hotelReviewId=%d): %w", review.HotelReviewID, err)
+		}
+		if err := models.LinkDuplicates(tx, &review); err != nil {
+			return fmt.Errorf("failed to link duplicates (hotelReviewId=%d): %w", review.HotelReviewID, err)
+		}
+		if err := models.ScoreSuspicion(tx, &review); err != nil {
+			return fmt.Errorf("failed to score suspicion (hotelReviewId=%d): %w", review.HotelReviewID, err)
+		}
+
+		// ✅ Rating summary update
+		if err := bumpRatingsSummary(tx, review.HotelID, 1, float64(review.NormalizedRating)); err != nil {
+			return fmt.Errorf("error updating hotel summary: %w", err)
+		}
+		if err := insertSubRatings(tx, review, subRatings); err != nil {
+			return err
+		}
+		if response != nil {
+			if err := savePlatformResponse(tx, review.ID, response); err != nil {
+				return err
+			}
+		}
+		if err := models.SaveAspectMentions(tx, review); err != nil {
+			return fmt.Errorf("failed to save aspect mentions (hotelReviewId=%d): %w", review.HotelReviewID, err)
+		}
+		outcome = OutcomeInserted
+		return models.EnqueueReviewEvent(tx, "insert", review, listing, platform)
+	})
+	if err != nil {
+		return OutcomeSkipped, err
 	}
+	return outcome, nil
+}
 
-	// ✅ Rating summary update
-	var summary models.HotelRatingsSummary
-	if err := db.Where("hotel_id = ?", hotel.ID).First(&summary).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			summary = models.HotelRatingsSummary{
-				HotelID:       hotel.ID,
-				TotalReviews:  1,
-				TotalRating:   float64(review.Rating),
-				AverageRating: float64(review.Rating),
-				LastUpdated:   time.Now(),
+// deleteReview removes a review named by a tombstone record of the form
+// {"op": "delete", "comment": {"hotelReviewId": ...}}.
+func deleteReview(raw map[string]interface{}, db *gorm.DB) (Outcome, error) {
+	comment, _ := raw["comment"].(map[string]interface{})
+	hotelReviewID, err := parseInt64(comment["hotelReviewId"])
+	if err != nil {
+		return OutcomeSkipped, fmt.Errorf("invalid hotelReviewId in delete record: %w", err)
+	}
+
+	var review models.Review
+	if err := db.Where("hotel_review_id = ?", hotelReviewID).First(&review).Error; err == gorm.ErrRecordNotFound {
+		return OutcomeSkipped, nil
+	} else if err != nil {
+		return OutcomeSkipped, fmt.Errorf("error checking for review (id=%d): %w", hotelReviewID, err)
+	}
+
+	var listing models.HotelPlatformListing
+	var platform models.Platform
+	db.First(&listing, review.ListingID)
+	db.First(&platform, review.PlatformID)
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		stored, err := storedSubRatings(tx, review.ID)
+		if err != nil {
+			return fmt.Errorf("error loading sub-ratings (id=%d): %w", hotelReviewID, err)
+		}
+		if err := deleteSubRatings(tx, review, stored); err != nil {
+			return err
+		}
+		if err := tx.Where("review_id = ?", review.ID).Delete(&models.ManagementResponse{}).Error; err != nil {
+			return fmt.Errorf("failed to delete responses (hotelReviewId=%d): %w", hotelReviewID, err)
+		}
+		if err := tx.Where("review_id = ?", review.ID).Delete(&models.ReviewAspectMention{}).Error; err != nil {
+			return fmt.Errorf("failed to delete aspect mentions (hotelReviewId=%d): %w", hotelReviewID, err)
+		}
+		if err := models.UnlinkDuplicate(tx, review); err != nil {
+			return fmt.Errorf("failed to unlink duplicates (hotelReviewId=%d): %w", hotelReviewID, err)
+		}
+		if err := tx.Delete(&review).Error; err != nil {
+			return fmt.Errorf("failed to delete review (hotelReviewId=%d): %w", hotelReviewID, err)
+		}
+		if review.Visible() {
+			if err := bumpRatingsSummary(tx, review.HotelID, -1, -float64(review.NormalizedRating)); err != nil {
+				return fmt.Errorf("error updating hotel summary: %w", err)
+			}
+			if err := models.RescoreNeighbours(tx, review); err != nil {
+				return fmt.Errorf("failed to rescore suspicion (hotelReviewId=%d): %w", hotelReviewID, err)
 			}
-			db.Create(&summary)
-		} else {
-			log.Printf("❌ Error loading hotel summary: %v", err)
 		}
-	} else {
-		summary.TotalReviews++
-		summary.TotalRating += float64(review.Rating)
-		summary.AverageRating = summary.TotalRating / float64(summary.TotalReviews)
-		summary.LastUpdated = time.Now()
-		db.Save(&summary)
+		return models.EnqueueReviewEvent(tx, "delete", review, listing, platform)
+	})
+	if err != nil {
+		return OutcomeSkipped, err
 	}
+	return OutcomeDeleted, nil
+}
+
+// updateReview overwrites a stored review and its sub-ratings with the
+// incoming record and moves its contribution to the ratings summaries if the
+// ratings or hotel changed. Summaries are kept on the normalized rating and
+// only count visible reviews; moderation is left as it was.
+func updateReview(db *gorm.DB, existing, incoming models.Review, subs []models.ReviewSubRating) (Outcome, error) {
+	incoming.ID = existing.ID
+	incoming.CreatedAt = existing.CreatedAt
+	incoming.ReviewerID = existing.ReviewerID
+	incoming.ModerationStatus, incoming.ModerationReason = existing.ModerationStatus, existing.ModerationReason
+	incoming.ModeratedBy, incoming.ModeratedAt = existing.ModeratedBy, existing.ModeratedAt
+	// The duplicate cluster stands while the text, hotel and date it was
+	// found with are unchanged; otherwise the review is compared again.
+	relink := existing.HotelID != incoming.HotelID ||
+		!bytes.Equal(existing.MinHash, incoming.MinHash) ||
+		!sameTime(existing.ReviewDate, incoming.ReviewDate)
+	if !relink {
+		incoming.DuplicateClusterID, incoming.DuplicateSimilarity = existing.DuplicateClusterID, existing.DuplicateSimilarity
+	}
+
+	stored, err := storedSubRatings(db, existing.ID)
+	if err != nil {
+		return OutcomeSkipped, fmt.Errorf("error loading sub-ratings (id=%d): %w", existing.HotelReviewID, err)
+	}
+	subsChanged := existing.HotelID != incoming.HotelID || !sameSubRatings(stored, subs)
+	if sameReview(existing, incoming) && !subsChanged {
+		return OutcomeUnchanged, nil
+	}
+
+	if relink {
+		if err := models.UnlinkDuplicate(db, existing); err != nil {
+			return OutcomeSkipped, fmt.Errorf("failed to unlink duplicates (hotelReviewId=%d): %w", incoming.HotelReviewID, err)
+		}
+	}
+	if err := db.Save(&incoming).Error; err != nil {
+		return OutcomeSkipped, fmt.Errorf("failed to update review (hotelReviewId=%d): %w", incoming.HotelReviewID, err)
+	}
+	if relink {
+		if err := models.LinkDuplicates(db, &incoming); err != nil {
+			return OutcomeUpdated, fmt.Errorf("failed to link duplicates (hotelReviewId=%d): %w", incoming.HotelReviewID, err)
+		}
+	}
+	if err := models.ScoreSuspicion(db, &incoming); err != nil {
+		return OutcomeUpdated, fmt.Errorf("failed to score suspicion (hotelReviewId=%d): %w", incoming.HotelReviewID, err)
+	}
+
+	counted := existing.Visible()
+	if counted && existing.HotelID != incoming.HotelID {
+		if err := bumpRatingsSummary(db, existing.HotelID, -1, -float64(existing.NormalizedRating)); err != nil {
+			return OutcomeUpdated, fmt.Errorf("error updating hotel summary: %w", err)
+		}
+		if err := bumpRatingsSummary(db, incoming.HotelID, 1, float64(incoming.NormalizedRating)); err != nil {
+			return OutcomeUpdated, fmt.Errorf("error updating hotel summary: %w", err)
+		}
+	} else if counted && existing.NormalizedRating != incoming.NormalizedRating {
+		if err := bumpRatingsSummary(db, incoming.HotelID, 0, float64(incoming.NormalizedRating-existing.NormalizedRating)); err != nil {
+			return OutcomeUpdated, fmt.Errorf("error updating hotel summary: %w", err)
+		}
+	}
+
+	if subsChanged {
+		if err := deleteSubRatings(db, existing, stored); err != nil {
+			return OutcomeUpdated, err
+		}
+		if err := insertSubRatings(db, incoming, subs); err != nil {
+			return OutcomeUpdated, err
+		}
+	}
+	return OutcomeUpdated, nil
+}
+
+func sameReview(a, b models.Review) bool {
+	return a.HotelID == b.HotelID &&
+		a.PlatformID == b.PlatformID &&
+		a.ListingID == b.ListingID &&
+		sameID(a.CountryID, b.CountryID) &&
+		sameID(a.TravelerTypeID, b.TravelerTypeID) &&
+		sameID(a.RoomTypeID, b.RoomTypeID) &&
+		sameID(a.ReviewerProfileID, b.ReviewerProfileID) &&
+		sameTime(a.StayDate, b.StayDate) &&
+		a.LengthOfStay == b.LengthOfStay &&
+		a.Rating == b.Rating &&
+		a.NormalizedRating == b.NormalizedRating &&
+		a.ReviewTitle == b.ReviewTitle &&
+		a.ReviewText == b.ReviewText &&
+		sameString(a.PIIKinds, b.PIIKinds) &&
+		bytes.Equal(a.MinHash, b.MinHash) &&
+		sameTime(a.ReviewDate, b.ReviewDate) &&
+		a.ReviewDateRaw == b.ReviewDateRaw &&
+		sameInt(a.ReviewDateOffset, b.ReviewDateOffset) &&
+		sameTime(a.ReviewLocalDate, b.ReviewLocalDate) &&
+		a.Language == b.Language &&
+		sameFloat(a.SentimentScore, b.SentimentScore) &&
+		a.SentimentLabel == b.SentimentLabel &&
+		a.QualityFlags == b.QualityFlags
+}
+
+func sameID(a, b *uint) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+func sameInt(a, b *int) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+func sameString(a, b *string) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+func sameFloat(a, b *float32) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+func sameTime(a, b *time.Time) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && a.Equal(*b))
+}
+
+// bumpRatingsSummary applies a delta to a hotel's pre-aggregated ratings in a
+// single statement, so concurrent workers cannot lose each other's updates.
+func bumpRatingsSummary(db *gorm.DB, hotelID uint, reviews int, rating float64) error {
+	summary := models.HotelRatingsSummary{
+		HotelID:       hotelID,
+		TotalReviews:  reviews,
+		TotalRating:   rating,
+		AverageRating: rating,
+		LastUpdated:   time.Now(),
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "hotel_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"total_reviews": gorm.Expr("hotel_ratings_summaries.total_reviews + ?", reviews),
+			"total_rating":  gorm.Expr("hotel_ratings_summaries.total_rating + ?", rating),
+			"average_rating": gorm.Expr(
+				"COALESCE((hotel_ratings_summaries.total_rating + ?) / NULLIF(hotel_ratings_summaries.total_reviews + ?, 0), 0)",
+				rating, reviews),
+			"last_updated": summary.LastUpdated,
+		}),
+	}).Create(&summary).Error
 }
 
 func getStr(val interface{}) string {
@@ -152,20 +470,28 @@ func getStr(val interface{}) string {
 	return strings.TrimSpace(fmt.Sprintf("%v", val))
 }
 
-func parseInt64(val interface{}) int64 {
+func getFloat(val interface{}) *float64 {
+	if f, ok := val.(float64); ok {
+		return &f
+	}
+	return nil
+}
+
+// parseInt64 reads an integer sent as a JSON number or a string. Missing or
+// malformed values are an error rather than 0, so they can't pass for a real
+// ID.
+func parseInt64(val interface{}) (int64, error) {
 	switch v := val.(type) {
 	case float64:
-		return int64(v)
+		if v != math.Trunc(v) {
+			return 0, fmt.Errorf("%v is not an integer", v)
+		}
+		return int64(v), nil
 	case string:
-		var parsed int64
-		fmt.Sscanf(v, "%d", &parsed)
-		return parsed
+		return strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	case nil:
+		return 0, errors.New("missing value")
 	default:
-		return 0
+		return 0, fmt.Errorf("unexpected type %T", val)
 	}
 }
-
-func parseTime(val string) time.Time {
-	t, _ := time.Parse(time.RFC3339, val)
-	return t
-}
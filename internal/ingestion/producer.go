@@ -1,30 +1,37 @@
 package ingestion
 
 import (
-	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
-	"net/http"
+	"net"
 	"os"
+	"path"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"review-system/internal/storage"
+
 	"github.com/segmentio/kafka-go"
 )
 
 var (
 	KafkaTopic      = getEnv("KAFKA_TOPIC", "reviews.raw")
 	KafkaBrokers    = strings.Split(getEnv("KAFKA_BROKERS", ""), ",")
-	S3Bucket        = getEnv("S3_BUCKET", "your-s3-bucket")
-	S3Prefix        = getEnv("S3_PREFIX", "your/s3/path")
+	S3Prefix        = getEnv("S3_PREFIX", "")
 	ProcessedMarker = getEnv("PROCESSED_LOG", "processed.log")
-	envMap          = make(map[string]string)
 	BatchSize       = 50
+	MaxLineBytes    = 10 * 1024 * 1024
+
+	ProducerAcks         = parseAcks(getEnv("KAFKA_PRODUCER_ACKS", "all"))
+	ProducerCompression  = parseCompression(getEnv("KAFKA_PRODUCER_COMPRESSION", "snappy"))
+	ProducerBatchBytes   = int64(getEnvInt("KAFKA_PRODUCER_BATCH_BYTES", 1048576))
+	ProducerWriteTimeout = getEnvDuration("KAFKA_PRODUCER_WRITE_TIMEOUT", 10*time.Second)
+	ProducerMaxAttempts  = getEnvInt("KAFKA_PRODUCER_MAX_ATTEMPTS", 5)
+	ProducerRetryBackoff = getEnvDuration("KAFKA_PRODUCER_RETRY_BACKOFF", 500*time.Millisecond)
 )
 
 func getEnv(key, fallback string) string {
@@ -34,178 +41,216 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
-func EnsureTopic() {
-	var conn *kafka.Conn
-	var err error
-
-	topic := KafkaTopic
-	for _, broker := range KafkaBrokers {
-		conn, err = kafka.Dial("tcp", broker)
-		if err != nil {
-			log.Printf("⚠️ Kafka dial failed on broker %s: %v", broker, err)
-			continue
-		}
-		log.Printf("🔗 Connected to Kafka broker: %s", broker)
-		break
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(getEnv(key, "")); err == nil {
+		return d
 	}
+	return fallback
+}
 
-	if conn == nil {
-		log.Printf("❌ Could not connect to any Kafka broker")
-		return
+func getEnvInt(key string, fallback int) int {
+	if v, err := strconv.Atoi(getEnv(key, "")); err == nil {
+		return v
 	}
-	defer conn.Close()
+	return fallback
+}
 
-	err = conn.CreateTopics(kafka.TopicConfig{
-		Topic:             topic,
-		NumPartitions:     3,
-		ReplicationFactor: 2,
-	})
-	if err != nil {
-		log.Printf("⚠️ Topic creation failed (might already exist): %v", err)
-	} else {
-		log.Printf("✅ Kafka topic %s ensured", topic)
+func parseAcks(val string) kafka.RequiredAcks {
+	var acks kafka.RequiredAcks
+	if err := acks.UnmarshalText([]byte(val)); err != nil {
+		log.Printf("⚠️  Invalid KAFKA_PRODUCER_ACKS %q, using all: %v", val, err)
+		return kafka.RequireAll
 	}
+	return acks
 }
 
-func StartS3StreamIngestion() {
-	EnsureTopic()
-
-	envMap := make(map[string]string)
-	for _, e := range os.Environ() {
-		parts := strings.SplitN(e, "=", 2)
-		if len(parts) == 2 {
-			envMap[parts[0]] = parts[1]
-		}
+func parseCompression(val string) kafka.Compression {
+	var c kafka.Compression
+	if err := c.UnmarshalText([]byte(val)); err != nil {
+		log.Printf("⚠️  Invalid KAFKA_PRODUCER_COMPRESSION %q, sending uncompressed: %v", val, err)
+		return 0
 	}
+	return c
+}
 
+// StartS3StreamIngestion produces today's file from the object store in the
+// background.
+func StartS3StreamIngestion(store storage.ObjectStore) {
 	go func() {
 		today := time.Now().UTC().Format("2006-01-02")
-		key := fmt.Sprintf("%s/%s.jl", envMap["S3_PREFIX"], today)
-		marker := fmt.Sprintf("%s/%s", envMap["S3_BUCKET"], key)
-
-		if alreadyProcessed(marker) {
-			log.Printf("✅ File already processed: %s", marker)
-			return
-		}
-
-		if err := StreamAndBulkProduceFromS3Read(envMap["S3_BUCKET"], key); err != nil {
-			log.Printf("❌ Error streaming from S3: %v", err)
-		} else {
-			markAsProcessed(marker)
-			log.Printf("✅ Successfully streamed: %s", marker)
+		if err := IngestDailyFile(context.Background(), store, today); err != nil {
+			log.Printf("❌ Error streaming %s: %v", today, err)
 		}
 	}()
 }
 
-func StreamAndBulkProduceFromS3Read(bucket, key string) error {
-	region := os.Getenv("AWS_REGION")
-	if region == "" {
-		region = "ap-south-1" // fallback
-	}
+// IngestDailyFile produces the file for day (YYYY-MM-DD) and marks it as
+// processed, unless it already was.
+func IngestDailyFile(ctx context.Context, store storage.ObjectStore, day string) error {
+	key := path.Join(S3Prefix, day+".jl")
+	marker := fmt.Sprintf("%s/%s", store.Name(), key)
 
-	// Construct public URL
-	url := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", bucket, region, key)
-	log.Printf("🌐 Fetching public S3 file: %s", url)
+	if alreadyProcessed(marker) {
+		log.Printf("✅ File already processed: %s", marker)
+		return nil
+	}
 
-	resp, err := http.Get(url)
+	info, err := store.Stat(ctx, key)
 	if err != nil {
-		return fmt.Errorf("failed to fetch public S3 file: %w", err)
+		return err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("non-200 status from S3: %s", resp.Status)
+	if err := StreamAndBulkProduce(ctx, store, info, marker); err != nil {
+		return err
 	}
+	markAsProcessed(marker)
+	clearCheckpoint(marker)
+	log.Printf("✅ Successfully streamed: %s", marker)
+	return nil
+}
 
-	writer := kafka.NewWriter(kafka.WriterConfig{
-		Brokers:  KafkaBrokers,
-		Topic:    KafkaTopic,
-		Balancer: &kafka.LeastBytes{},
-	})
-	defer writer.Close()
-
-	log.Printf("📤 Streaming public S3 and producing in batches of %d...", BatchSize)
-
-	scanner := bufio.NewScanner(resp.Body)
-	var batch []kafka.Message
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		batch = append(batch, kafka.Message{Value: []byte(line)})
+// StreamAndBulkProduce streams an object from the store into the raw topic.
+func StreamAndBulkProduce(ctx context.Context, store storage.ObjectStore, info storage.ObjectInfo, marker string) error {
+	log.Printf("📤 Streaming %s (%d bytes) and producing in batches of %d...", marker, info.Size, BatchSize)
+	return produceLines(ctx, objectOpener(store, info), marker, info.ETag)
+}
 
-		if len(batch) >= BatchSize {
-			if err := writer.WriteMessages(context.Background(), batch...); err != nil {
-				return fmt.Errorf("❌ failed to write batch: %w", err)
-			}
-			batch = batch[:0]
+// objectOpener opens info from store at a byte offset. An offset at or past
+// the end has nothing left to read, and S3 answers a range starting there
+// with 416 InvalidRange, so no request is made for it.
+func objectOpener(store storage.ObjectStore, info storage.ObjectInfo) rangeOpener {
+	return func(ctx context.Context, offset int64) (io.ReadCloser, error) {
+		if offset > 0 && offset >= info.Size {
+			return io.NopCloser(strings.NewReader("")), nil
 		}
-	}
-
-	if len(batch) > 0 {
-		if err := writer.WriteMessages(context.Background(), batch...); err != nil {
-			return fmt.Errorf("❌ failed to write final batch: %w", err)
+		body, err := store.Open(ctx, info.Key, offset)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, permanentError{err}
 		}
+		return body, err
 	}
-
-	return scanner.Err()
 }
 
-func StreamAndBulkProduceFromS3(bucket, key string) error {
-	awsKey := os.Getenv("AWS_ACCESS_KEY_ID")
-	awsSecret := os.Getenv("AWS_SECRET_ACCESS_KEY")
-	awsRegion := os.Getenv("AWS_REGION")
+// produceLines sends every line of the object to the raw topic in batches.
+// After each acknowledged batch it checkpoints the line number and byte
+// offset reached under marker, and a later run for the same marker resumes
+// from there with a ranged read instead of producing the file again. A
+// checkpoint taken against a different etag is discarded.
+func produceLines(ctx context.Context, open rangeOpener, marker, etag string) error {
+	writer := newProducer()
+	defer writer.Close()
 
-	if awsKey == "" || awsSecret == "" || awsRegion == "" {
-		return fmt.Errorf("missing AWS credentials or region in environment")
+	cp := loadCheckpoint(marker)
+	if cp.ETag != "" && cp.ETag != etag {
+		log.Printf("🔄 %s changed since its checkpoint, producing it from the start", marker)
+		cp = checkpoint{}
+	}
+	if cp.Line > 0 {
+		log.Printf("⏩ Resuming %s after line %d (byte %d)", marker, cp.Line, cp.Offset)
 	}
 
-	cfg, err := config.LoadDefaultConfig(context.TODO(),
-		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(awsKey, awsSecret, "")),
-		config.WithRegion(awsRegion),
-	)
-	if err != nil {
-		return fmt.Errorf("unable to load AWS config: %w", err)
+	var batch []kafka.Message
+	lineNo, offset := cp.Line, cp.Offset
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := writeWithRetry(ctx, writer, batch); err != nil {
+			return fmt.Errorf("failed to write batch ending at line %d: %w", lineNo, err)
+		}
+		saveCheckpoint(marker, checkpoint{Line: lineNo, Offset: offset, ETag: etag})
+		batch = batch[:0]
+		return nil
 	}
 
-	s3client := s3.NewFromConfig(cfg)
-	resp, err := s3client.GetObject(context.TODO(), &s3.GetObjectInput{
-		Bucket: aws.String(bucket),
-		Key:    aws.String(key),
+	err := streamLines(ctx, open, cp.Offset, func(line []byte, end int64) error {
+		lineNo, offset = lineNo+1, end
+		batch = append(batch, kafka.Message{Value: append([]byte(nil), line...)})
+		if len(batch) >= BatchSize {
+			return flush()
+		}
+		return nil
 	})
 	if err != nil {
-		return fmt.Errorf("failed to fetch S3 object: %s %s %w", bucket, key, err)
+		return err
 	}
-	defer resp.Body.Close()
+	return flush()
+}
 
-	writer := kafka.NewWriter(kafka.WriterConfig{
-		Brokers:  KafkaBrokers,
-		Topic:    KafkaTopic,
-		Balancer: &kafka.LeastBytes{},
-	})
-	defer writer.Close()
+// newProducer builds a writer for the raw topic from the KAFKA_PRODUCER_*
+// settings. kafka-go has no idempotent producer, so duplicates caused by a
+// retried batch are absorbed by the consumer's hotelReviewId dedupe instead.
+func newProducer() *kafka.Writer {
+	return &kafka.Writer{
+		Addr:         kafka.TCP(KafkaBrokers...),
+		Topic:        KafkaTopic,
+		Balancer:     &kafka.LeastBytes{},
+		RequiredAcks: ProducerAcks,
+		Compression:  ProducerCompression,
+		BatchSize:    BatchSize,
+		BatchBytes:   ProducerBatchBytes,
+		WriteTimeout: ProducerWriteTimeout,
+		MaxAttempts:  1, // retries are handled by writeWithRetry
+		ErrorLogger: kafka.LoggerFunc(func(msg string, args ...interface{}) {
+			log.Printf("⚠️  Kafka producer: "+msg, args...)
+		}),
+	}
+}
 
-	log.Printf("📤 Producing records in batches of %d...", BatchSize)
+// writeWithRetry writes msgs, retrying transient failures with exponential
+// backoff. When the broker rejects only some messages, only those are resent.
+func writeWithRetry(ctx context.Context, w *kafka.Writer, msgs []kafka.Message) error {
+	backoff := ProducerRetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := w.WriteMessages(ctx, msgs...)
+		if err == nil {
+			return nil
+		}
+		if attempt >= ProducerMaxAttempts || !isTransient(err) {
+			return err
+		}
 
-	scanner := bufio.NewScanner(resp.Body)
-	var batch []kafka.Message
+		var writeErrs kafka.WriteErrors
+		if errors.As(err, &writeErrs) {
+			failed := msgs[:0:0]
+			for i, e := range writeErrs {
+				if e != nil {
+					failed = append(failed, msgs[i])
+				}
+			}
+			msgs = failed
+		}
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		batch = append(batch, kafka.Message{Value: []byte(line)})
+		log.Printf("⚠️  Produce attempt %d/%d failed, retrying in %s: %v", attempt, ProducerMaxAttempts, backoff, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
 
-		if len(batch) >= BatchSize {
-			if err := writer.WriteMessages(context.Background(), batch...); err != nil {
-				return fmt.Errorf("❌ failed to write batch: %w", err)
+func isTransient(err error) bool {
+	var writeErrs kafka.WriteErrors
+	if errors.As(err, &writeErrs) {
+		for _, e := range writeErrs {
+			if e != nil && !isTransient(e) {
+				return false
 			}
-			batch = batch[:0]
 		}
+		return true
 	}
 
-	if len(batch) > 0 {
-		if err := writer.WriteMessages(context.Background(), batch...); err != nil {
-			return fmt.Errorf("❌ failed to write final batch: %w", err)
-		}
+	var kafkaErr kafka.Error
+	if errors.As(err, &kafkaErr) {
+		return kafkaErr.Temporary()
 	}
 
-	return scanner.Err()
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, context.DeadlineExceeded)
 }
@@ -0,0 +1,20 @@
+package ingestion
+
+import (
+	"log"
+
+	"review-system/internal/quality"
+)
+
+// QualityEngine checks every record before it is written. Rule actions can
+// be overridden with QUALITY_RULES, see quality.LoadRules.
+var QualityEngine = newQualityEngine(getEnv("QUALITY_RULES", ""))
+
+func newQualityEngine(spec string) *quality.Engine {
+	rules, err := quality.LoadRules(spec)
+	if err != nil {
+		log.Printf("⚠️  Invalid QUALITY_RULES, using the default rules: %v", err)
+		rules = quality.DefaultRules
+	}
+	return quality.NewEngine(rules)
+}
@@ -0,0 +1,48 @@
+package ingestion
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"review-system/internal/quality"
+
+	"github.com/segmentio/kafka-go"
+)
+
+var (
+	dlqWriter     *kafka.Writer
+	dlqWriterOnce sync.Once
+)
+
+// quarantine publishes a record that failed a quarantine rule to the DLQ
+// topic, with the rules it broke in the quality-rules header. Records can be
+// fed back into the pipeline with cmd/replay once fixed.
+func quarantine(raw map[string]interface{}, res quality.Result) error {
+	dlqWriterOnce.Do(func() {
+		dlqWriter = &kafka.Writer{
+			Addr:         kafka.TCP(KafkaBrokers...),
+			Topic:        KafkaDLQTopic,
+			Balancer:     &kafka.LeastBytes{},
+			RequiredAcks: kafka.RequireAll,
+			MaxAttempts:  5,
+		}
+	})
+
+	value, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ProducerWriteTimeout)
+	defer cancel()
+	return dlqWriter.WriteMessages(ctx, kafka.Message{
+		Value: value,
+		Time:  time.Now(),
+		Headers: []kafka.Header{
+			{Key: "quality-action", Value: []byte(res.Action.String())},
+			{Key: "quality-rules", Value: []byte(strings.Join(res.Flags(), ","))},
+		},
+	})
+}
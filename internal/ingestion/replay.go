@@ -0,0 +1,233 @@
+package ingestion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"review-system/models"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// ReplayOptions selects the slice of a topic to reprocess. Start and end can
+// be given as timestamps, offsets or both; when both are set the narrower
+// bound wins. Zero values mean "from the beginning" and "up to the current
+// end of the partition".
+type ReplayOptions struct {
+	Topic       string
+	Partitions  []int // empty means every partition of the topic
+	From        time.Time
+	To          time.Time
+	StartOffset int64 // inclusive, -1 to ignore
+	EndOffset   int64 // exclusive, -1 to ignore
+}
+
+// ReplayStats counts what a replay did with the messages it read.
+type ReplayStats struct {
+	Read        int64
+	Inserted    int64
+	Updated     int64
+	Unchanged   int64
+	Deleted     int64
+	Skipped     int64
+	Rejected    int64
+	Quarantined int64
+	Invalid     int64
+	Failed      int64
+}
+
+func (s *ReplayStats) String() string {
+	return fmt.Sprintf("read=%d inserted=%d updated=%d unchanged=%d deleted=%d skipped=%d rejected=%d quarantined=%d invalid=%d failed=%d",
+		s.Read, s.Inserted, s.Updated, s.Unchanged, s.Deleted, s.Skipped, s.Rejected, s.Quarantined, s.Invalid, s.Failed)
+}
+
+// Replay re-reads a window of a topic and writes every record with upsert
+// semantics. It uses partition readers without a consumer group, so nothing is
+// committed and the live consumer group's offsets are left untouched.
+func Replay(ctx context.Context, opts ReplayOptions) (*ReplayStats, error) {
+	if opts.Topic == "" {
+		opts.Topic = KafkaTopic
+	}
+
+	partitions := opts.Partitions
+	if len(partitions) == 0 {
+		var err error
+		if partitions, err = topicPartitions(opts.Topic); err != nil {
+			return nil, err
+		}
+	}
+
+	stats := &ReplayStats{}
+	errCh := make(chan error, len(partitions))
+	var wg sync.WaitGroup
+
+	for _, p := range partitions {
+		wg.Add(1)
+		go func(partition int) {
+			defer wg.Done()
+			if err := replayPartition(ctx, opts, partition, stats); err != nil {
+				errCh <- fmt.Errorf("partition %d: %w", partition, err)
+			}
+		}(p)
+	}
+	wg.Wait()
+	close(errCh)
+
+	if err := <-errCh; err != nil {
+		return stats, err
+	}
+	return stats, nil
+}
+
+func topicPartitions(topic string) ([]int, error) {
+	for _, broker := range KafkaBrokers {
+		conn, err := kafka.Dial("tcp", broker)
+		if err != nil {
+			log.Printf("⚠️ Kafka dial failed on broker %s: %v", broker, err)
+			continue
+		}
+		defer conn.Close()
+
+		parts, err := conn.ReadPartitions(topic)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read partitions for %s: %w", topic, err)
+		}
+		ids := make([]int, 0, len(parts))
+		for _, p := range parts {
+			ids = append(ids, p.ID)
+		}
+		return ids, nil
+	}
+	return nil, fmt.Errorf("could not connect to any Kafka broker")
+}
+
+// partitionBounds returns the first and next-to-be-written offsets of a
+// partition, as reported by its leader.
+func partitionBounds(ctx context.Context, topic string, partition int) (int64, int64, error) {
+	var lastErr error
+	for _, broker := range KafkaBrokers {
+		conn, err := kafka.DialLeader(ctx, "tcp", broker, topic, partition)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		defer conn.Close()
+		return conn.ReadOffsets()
+	}
+	return 0, 0, fmt.Errorf("could not reach partition leader: %w", lastErr)
+}
+
+// offsetAt returns the offset of the first message of a partition produced at
+// or after t, or last when there is none yet. Kafka answers -1 for such a t,
+// and seeking a reader there would wait for new messages instead of
+// returning.
+func offsetAt(ctx context.Context, topic string, partition int, t time.Time, last int64) (int64, error) {
+	var lastErr error
+	for _, broker := range KafkaBrokers {
+		conn, err := kafka.DialLeader(ctx, "tcp", broker, topic, partition)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		defer conn.Close()
+		offset, err := conn.ReadOffset(t)
+		if err != nil {
+			return 0, err
+		}
+		if offset < 0 || offset > last {
+			offset = last
+		}
+		return offset, nil
+	}
+	return 0, fmt.Errorf("could not reach partition leader: %w", lastErr)
+}
+
+func replayPartition(ctx context.Context, opts ReplayOptions, partition int, stats *ReplayStats) error {
+	first, last, err := partitionBounds(ctx, opts.Topic, partition)
+	if err != nil {
+		return err
+	}
+
+	end := last
+	if opts.EndOffset >= 0 && opts.EndOffset < end {
+		end = opts.EndOffset
+	}
+
+	start := first
+	if !opts.From.IsZero() {
+		if start, err = offsetAt(ctx, opts.Topic, partition, opts.From, last); err != nil {
+			return fmt.Errorf("failed to find offset at %s: %w", opts.From.Format(time.RFC3339), err)
+		}
+	}
+	if opts.StartOffset > start {
+		start = opts.StartOffset
+	}
+	if start >= end {
+		log.Printf("⏭️  Partition %d has nothing to replay in [%d, %d)", partition, start, end)
+		return nil
+	}
+
+	r := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   KafkaBrokers,
+		Topic:     opts.Topic,
+		Partition: partition,
+		MinBytes:  1e4,
+		MaxBytes:  10e6,
+		MaxWait:   100 * time.Millisecond,
+	})
+	defer r.Close()
+
+	if err := r.SetOffset(start); err != nil {
+		return fmt.Errorf("failed to seek to offset %d: %w", start, err)
+	}
+
+	log.Printf("🔁 Replaying %s[%d] offsets [%d, %d)", opts.Topic, partition, start, end)
+
+	db := models.GetDB()
+	for {
+		m, err := r.ReadMessage(ctx)
+		if err != nil {
+			return err
+		}
+		if !opts.To.IsZero() && m.Time.After(opts.To) {
+			return nil
+		}
+
+		atomic.AddInt64(&stats.Read, 1)
+
+		var raw map[string]interface{}
+		if err := json.Unmarshal(m.Value, &raw); err != nil {
+			atomic.AddInt64(&stats.Invalid, 1)
+		} else {
+			outcome, err := UpsertJLLine(raw, db)
+			switch {
+			case err != nil:
+				log.Printf("❌ [Replay %d@%d] %v", partition, m.Offset, err)
+				atomic.AddInt64(&stats.Failed, 1)
+			case outcome == OutcomeInserted:
+				atomic.AddInt64(&stats.Inserted, 1)
+			case outcome == OutcomeUpdated:
+				atomic.AddInt64(&stats.Updated, 1)
+			case outcome == OutcomeUnchanged:
+				atomic.AddInt64(&stats.Unchanged, 1)
+			case outcome == OutcomeDeleted:
+				atomic.AddInt64(&stats.Deleted, 1)
+			case outcome == OutcomeRejected:
+				atomic.AddInt64(&stats.Rejected, 1)
+			case outcome == OutcomeQuarantined:
+				atomic.AddInt64(&stats.Quarantined, 1)
+			default:
+				atomic.AddInt64(&stats.Skipped, 1)
+			}
+		}
+
+		if m.Offset+1 >= end {
+			return nil
+		}
+	}
+}
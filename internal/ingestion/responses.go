@@ -0,0 +1,67 @@
+package ingestion
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"review-system/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// parseResponse reads the property's public reply from a provider record,
+// sent as comment.responseText, comment.responderName and
+// comment.responseDate, with personal data redacted. It returns nil when the
+// review has no reply.
+func parseResponse(comment map[string]interface{}) *models.ManagementResponse {
+	text := getStr(comment["responseText"])
+	if text == "" {
+		return nil
+	}
+	resp := &models.ManagementResponse{
+		Source: models.ResponseSourcePlatform,
+		Author: getStr(comment["responderName"]),
+		Text:   text,
+	}
+	// A missing or unparseable date is stored as NULL rather than year 1.
+	date, err := parseReviewDate(comment["responseDate"])
+	if err != nil {
+		log.Printf("⚠️  Ignoring unparseable responseDate %q", date.Raw)
+	} else if !date.At.IsZero() {
+		resp.ResponseDate = &date.At
+	}
+	RedactResponse(resp)
+	return resp
+}
+
+// savePlatformResponse stores the platform's reply to a review, replacing
+// the previous one, or removes it if the platform no longer shows a reply.
+// Local drafts are left alone.
+func savePlatformResponse(tx *gorm.DB, reviewID uint, resp *models.ManagementResponse) error {
+	if resp == nil {
+		if err := tx.Where("review_id = ? AND source = ?", reviewID, models.ResponseSourcePlatform).
+			Delete(&models.ManagementResponse{}).Error; err != nil {
+			return fmt.Errorf("failed to delete response (review=%d): %w", reviewID, err)
+		}
+		return nil
+	}
+
+	row := *resp
+	row.ReviewID = reviewID
+	err := tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "review_id"}, {Name: "source"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"author":        row.Author,
+			"text":          row.Text,
+			"pii_kinds":     row.PIIKinds,
+			"response_date": row.ResponseDate,
+			"updated_at":    time.Now(),
+		}),
+	}).Create(&row).Error
+	if err != nil {
+		return fmt.Errorf("failed to save response (review=%d): %w", reviewID, err)
+	}
+	return nil
+}
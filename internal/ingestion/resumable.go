@@ -0,0 +1,117 @@
+package ingestion
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+)
+
+var (
+	DownloadMaxRetries   = getEnvInt("S3_DOWNLOAD_MAX_RETRIES", 5)
+	DownloadRetryBackoff = getEnvDuration("S3_DOWNLOAD_RETRY_BACKOFF", time.Second)
+)
+
+// rangeOpener opens an object for reading starting at a byte offset.
+type rangeOpener func(ctx context.Context, offset int64) (io.ReadCloser, error)
+
+// permanentError marks a failure that retrying the request cannot fix, such
+// as a missing object or an oversized line.
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// streamLines calls fn for every newline-terminated line of the object,
+// starting at byte offset start. fn also receives the offset just past the
+// line, which is where a later run can resume. When the connection fails the
+// object is reopened at the end of the last complete line, so a partial line
+// is never handed to fn; after DownloadMaxRetries attempts without progress
+// the error is returned.
+func streamLines(ctx context.Context, open rangeOpener, start int64, fn func(line []byte, end int64) error) error {
+	offset := start
+	retries := 0
+	backoff := DownloadRetryBackoff
+
+	for {
+		body, err := open(ctx, offset)
+		if err == nil {
+			var progressed bool
+			offset, progressed, err = readLines(body, offset, fn)
+			body.Close()
+			if err == nil {
+				return nil
+			}
+			if progressed {
+				retries, backoff = 0, DownloadRetryBackoff
+			}
+		}
+
+		var cbErr callbackError
+		var permErr permanentError
+		if errors.As(err, &cbErr) {
+			return cbErr.err
+		}
+		if errors.As(err, &permErr) || ctx.Err() != nil || retries >= DownloadMaxRetries {
+			return err
+		}
+
+		retries++
+		log.Printf("⚠️  Download interrupted at byte %d (retry %d/%d in %s): %v", offset, retries, DownloadMaxRetries, backoff, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// callbackError carries an error returned by the line callback, which must
+// not be retried like a network error.
+type callbackError struct{ err error }
+
+func (e callbackError) Error() string { return e.err.Error() }
+
+// readLines consumes body until EOF or a read error, returning the offset
+// just past the last complete line and whether any line was read.
+func readLines(body io.Reader, offset int64, fn func(line []byte, end int64) error) (int64, bool, error) {
+	reader := bufio.NewReaderSize(body, 64*1024)
+	var partial []byte
+	progressed := false
+
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		partial = append(partial, chunk...)
+
+		if err == bufio.ErrBufferFull {
+			if len(partial) > MaxLineBytes {
+				return offset, progressed, permanentError{fmt.Errorf("line at byte %d exceeds %d bytes", offset, MaxLineBytes)}
+			}
+			continue
+		}
+
+		// A final line without a trailing newline is still a complete record.
+		if err == nil || (err == io.EOF && len(partial) > 0) {
+			end := offset + int64(len(partial))
+			if line := bytes.TrimRight(partial, "\r\n"); len(line) > 0 {
+				if cbErr := fn(line, end); cbErr != nil {
+					return offset, progressed, callbackError{cbErr}
+				}
+			}
+			offset, progressed = end, true
+			partial = partial[:0]
+		}
+
+		if err == io.EOF {
+			return offset, progressed, nil
+		}
+		if err != nil {
+			return offset, progressed, err
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package ingestion
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"review-system/internal/storage"
+)
+
+// flakyObject serves one object over the S3 path-style API and drops the
+// connection after dropAfter bytes of each of the first drops responses.
+type flakyObject struct {
+	body      []byte
+	dropAfter int
+
+	mu     sync.Mutex
+	drops  int
+	ranges []string
+}
+
+func (o *flakyObject) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	o.ranges = append(o.ranges, r.Header.Get("Range"))
+	drop := o.drops > 0
+	if drop {
+		o.drops--
+	}
+	o.mu.Unlock()
+
+	if drop {
+		w = &droppingWriter{ResponseWriter: w, left: o.dropAfter}
+	}
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(o.body))
+}
+
+// droppingWriter aborts the connection once left bytes have been written,
+// leaving the client with a body shorter than its Content-Length.
+type droppingWriter struct {
+	http.ResponseWriter
+	left int
+}
+
+func (w *droppingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.left {
+		w.ResponseWriter.Write(p[:w.left])
+		w.ResponseWriter.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	}
+	w.left -= len(p)
+	return w.ResponseWriter.Write(p)
+}
+
+func newTestStore(t *testing.T, obj *flakyObject) (storage.ObjectStore, storage.ObjectInfo) {
+	t.Helper()
+	srv := httptest.NewServer(obj)
+	t.Cleanup(srv.Close)
+
+	store, err := storage.NewS3Store(context.Background(), storage.S3Config{
+		Bucket:       "reviews",
+		Region:       "us-east-1",
+		Endpoint:     srv.URL,
+		UsePathStyle: true,
+		Anonymous:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store, storage.ObjectInfo{Key: "2024-01-01.jl", Size: int64(len(obj.body))}
+}
+
+func testLines(n int) (string, []string) {
+	var b strings.Builder
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf(`{"hotelReviewId":%d,"comment":"line %d"}`, i, i)
+		b.WriteString(lines[i] + "\n")
+	}
+	return b.String(), lines
+}
+
+func TestStreamLinesResumesAfterDroppedConnections(t *testing.T) {
+	DownloadRetryBackoff = time.Millisecond
+	body, want := testLines(200)
+	obj := &flakyObject{body: []byte(body), dropAfter: 1500, drops: 3}
+	store, info := newTestStore(t, obj)
+
+	var got []string
+	var lastEnd int64
+	err := streamLines(context.Background(), objectOpener(store, info), 0, func(line []byte, end int64) error {
+		if end <= lastEnd {
+			t.Fatalf("line %d ends at %d, not after %d", len(got), end, lastEnd)
+		}
+		lastEnd = end
+		got = append(got, string(line))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("got %d lines, want each of the %d lines once", len(got), len(want))
+	}
+	if lastEnd != int64(len(body)) {
+		t.Errorf("last line ends at %d, want %d", lastEnd, len(body))
+	}
+	if len(obj.ranges) != 4 || obj.ranges[0] != "" {
+		t.Fatalf("requests sent ranges %q, want a plain GET and 3 ranged ones", obj.ranges)
+	}
+	for _, r := range obj.ranges[1:] {
+		var offset int64
+		if _, err := fmt.Sscanf(r, "bytes=%d-", &offset); err != nil {
+			t.Fatalf("range %q: %v", r, err)
+		}
+		if offset == 0 || body[offset-1] != '\n' {
+			t.Errorf("range %q does not start after a complete line", r)
+		}
+	}
+}
+
+func TestStreamLinesGivesUpWithoutProgress(t *testing.T) {
+	DownloadRetryBackoff = time.Millisecond
+	body, _ := testLines(10)
+	obj := &flakyObject{body: []byte(body), dropAfter: 5, drops: DownloadMaxRetries + 1}
+	store, info := newTestStore(t, obj)
+
+	err := streamLines(context.Background(), objectOpener(store, info), 0, func([]byte, int64) error { return nil })
+	if err == nil {
+		t.Fatal("want an error after the retries run out")
+	}
+	if len(obj.ranges) != DownloadMaxRetries+1 {
+		t.Errorf("sent %d requests, want %d", len(obj.ranges), DownloadMaxRetries+1)
+	}
+}
+
+func TestStreamLinesFromEndOfObject(t *testing.T) {
+	body, _ := testLines(10)
+	obj := &flakyObject{body: []byte(body)}
+	store, info := newTestStore(t, obj)
+
+	err := streamLines(context.Background(), objectOpener(store, info), info.Size, func([]byte, int64) error {
+		t.Fatal("no line should be read past the end")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(obj.ranges) != 0 {
+		t.Errorf("sent ranges %q for a checkpoint at the end of the object", obj.ranges)
+	}
+}
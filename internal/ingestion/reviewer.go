@@ -0,0 +1,36 @@
+package ingestion
+
+import (
+	"time"
+
+	"review-system/models"
+)
+
+// stayMonthLayout is how Agoda reports the check-in month, e.g. "March 2025".
+const stayMonthLayout = "January 2006"
+
+// parseReviewerInfo reads comment.reviewerInfo. Only the country, traveler
+// group and room type are sent by every provider; the reviewer's identity
+// and stay details are optional.
+func parseReviewerInfo(info map[string]interface{}) models.ReviewerInfo {
+	reviewCount, _ := parseInt64(info["reviewerReviewedCount"])
+	lengthOfStay, _ := parseInt64(info["lengthOfStay"])
+	parsed := models.ReviewerInfo{
+		CountryName:  getStr(info["countryName"]),
+		TravelerType: getStr(info["reviewGroupName"]),
+		RoomTypeName: getStr(info["roomTypeName"]),
+		ReviewerID:   getStr(info["reviewerId"]),
+		DisplayName:  getStr(info["displayMemberName"]),
+		ReviewCount:  int(reviewCount),
+		LengthOfStay: int(lengthOfStay),
+	}
+	if parsed.ReviewerID == "" {
+		parsed.ReviewerID = getStr(info["memberId"])
+	}
+	if month := getStr(info["checkInDateMonthAndYear"]); month != "" {
+		if t, err := time.Parse(stayMonthLayout, month); err == nil {
+			parsed.StayDate = &t
+		}
+	}
+	return parsed
+}
@@ -0,0 +1,174 @@
+package ingestion
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"review-system/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// aspectFields maps the category names providers use to our aspects, keyed
+// by lowercased platform name. Names are matched case-insensitively; the ""
+// entry applies to every platform and unknown categories are ignored.
+var aspectFields = map[string]map[string]string{
+	"": {
+		"cleanliness": models.AspectCleanliness,
+		"location":    models.AspectLocation,
+		"staff":       models.AspectStaff,
+		"service":     models.AspectStaff,
+		"value":       models.AspectValue,
+		"facilities":  models.AspectFacilities,
+	},
+	"agoda": {
+		"staff performance": models.AspectStaff,
+		"value for money":   models.AspectValue,
+	},
+	"booking.com": {
+		"value for money": models.AspectValue,
+	},
+	"traveloka": {
+		"comfort": models.AspectFacilities,
+	},
+	"expedia": {
+		"hotel condition":  models.AspectFacilities,
+		"room cleanliness": models.AspectCleanliness,
+	},
+	"hotels.com": {
+		"hotel condition":  models.AspectFacilities,
+		"room cleanliness": models.AspectCleanliness,
+	},
+}
+
+// parseSubRatings reads the per-category scores a provider sends with a
+// review, as an object of category name to score under comment.grades or
+// comment.subRatings, and maps them onto aspects. Ratings are normalized
+// with the platform's scale.
+func parseSubRatings(comment map[string]interface{}, platform models.Platform) []models.ReviewSubRating {
+	grades, ok := comment["grades"].(map[string]interface{})
+	if !ok {
+		grades, _ = comment["subRatings"].(map[string]interface{})
+	}
+	if len(grades) == 0 {
+		return nil
+	}
+
+	fields := aspectFields[strings.ToLower(platform.Name)]
+	seen := make(map[string]bool)
+	var subs []models.ReviewSubRating
+	for name, val := range grades {
+		score, ok := val.(float64)
+		if !ok {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(name))
+		aspect, ok := fields[key]
+		if !ok {
+			aspect = aspectFields[""][key]
+		}
+		if aspect == "" || seen[aspect] {
+			continue
+		}
+		seen[aspect] = true
+		subs = append(subs, models.ReviewSubRating{
+			Aspect:           aspect,
+			Rating:           float32(score),
+			NormalizedRating: platform.Normalize(float32(score)),
+		})
+	}
+	return subs
+}
+
+// insertSubRatings stores a new review's sub-ratings and, if the review is
+// visible, adds them to its hotel's aspect summaries.
+func insertSubRatings(tx *gorm.DB, review models.Review, subs []models.ReviewSubRating) error {
+	for i := range subs {
+		subs[i].ID = 0
+		subs[i].ReviewID = review.ID
+	}
+	if len(subs) > 0 {
+		if err := tx.Create(&subs).Error; err != nil {
+			return fmt.Errorf("failed to insert sub-ratings (hotelReviewId=%d): %w", review.HotelReviewID, err)
+		}
+	}
+	if !review.Visible() {
+		return nil
+	}
+	for _, s := range subs {
+		if err := bumpAspectSummary(tx, review.HotelID, s.Aspect, 1, float64(s.NormalizedRating)); err != nil {
+			return fmt.Errorf("error updating aspect summary: %w", err)
+		}
+	}
+	return nil
+}
+
+// deleteSubRatings removes a review's stored sub-ratings and, if the review
+// is visible, takes them out of the aspect summaries of the hotel it was
+// counted under.
+func deleteSubRatings(tx *gorm.DB, review models.Review, stored []models.ReviewSubRating) error {
+	if len(stored) == 0 {
+		return nil
+	}
+	if err := tx.Where("review_id = ?", review.ID).Delete(&models.ReviewSubRating{}).Error; err != nil {
+		return fmt.Errorf("failed to delete sub-ratings (review=%d): %w", review.ID, err)
+	}
+	if !review.Visible() {
+		return nil
+	}
+	for _, s := range stored {
+		if err := bumpAspectSummary(tx, review.HotelID, s.Aspect, -1, -float64(s.NormalizedRating)); err != nil {
+			return fmt.Errorf("error updating aspect summary: %w", err)
+		}
+	}
+	return nil
+}
+
+func storedSubRatings(tx *gorm.DB, reviewID uint) ([]models.ReviewSubRating, error) {
+	var stored []models.ReviewSubRating
+	err := tx.Where("review_id = ?", reviewID).Find(&stored).Error
+	return stored, err
+}
+
+func sameSubRatings(a, b []models.ReviewSubRating) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	byAspect := make(map[string]models.ReviewSubRating, len(a))
+	for _, s := range a {
+		byAspect[s.Aspect] = s
+	}
+	for _, s := range b {
+		other, ok := byAspect[s.Aspect]
+		if !ok || other.Rating != s.Rating || other.NormalizedRating != s.NormalizedRating {
+			return false
+		}
+	}
+	return true
+}
+
+// bumpAspectSummary applies a delta to one of a hotel's aspect summaries in a
+// single statement, like bumpRatingsSummary.
+func bumpAspectSummary(db *gorm.DB, hotelID uint, aspect string, ratings int, rating float64) error {
+	summary := models.HotelAspectSummary{
+		HotelID:       hotelID,
+		Aspect:        aspect,
+		TotalRatings:  ratings,
+		TotalRating:   rating,
+		AverageRating: rating,
+		LastUpdated:   time.Now(),
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "hotel_id"}, {Name: "aspect"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"total_ratings": gorm.Expr("hotel_aspect_summaries.total_ratings + ?", ratings),
+			"total_rating":  gorm.Expr("hotel_aspect_summaries.total_rating + ?", rating),
+			"average_rating": gorm.Expr(
+				"COALESCE((hotel_aspect_summaries.total_rating + ?) / NULLIF(hotel_aspect_summaries.total_ratings + ?, 0), 0)",
+				rating, ratings),
+			"last_updated": summary.LastUpdated,
+		}),
+	}).Create(&summary).Error
+}
@@ -0,0 +1,211 @@
+package ingestion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+var (
+	KafkaDLQTopic   = getEnv("KAFKA_DLQ_TOPIC", KafkaTopic+".dlq")
+	KafkaRetryTopic = getEnv("KAFKA_RETRY_TOPIC", KafkaTopic+".retry")
+
+	// TopicConfigStrict makes a mismatch between the configured and the
+	// existing topic layout fatal instead of a warning.
+	TopicConfigStrict = getEnv("KAFKA_TOPIC_STRICT", "false") == "true"
+)
+
+// TopicSpec is the desired layout of a topic. Zero values leave the setting
+// to the broker default; a zero ReplicationFactor means "as many brokers as
+// the cluster has, up to 3".
+type TopicSpec struct {
+	Name              string
+	NumPartitions     int
+	ReplicationFactor int
+	RetentionMs       string
+	CleanupPolicy     string
+}
+
+// topicSpecs returns every topic the service reads or writes. Settings are
+// read from KAFKA_TOPIC_* and can be overridden per topic with the topic's own
+// prefix, e.g. KAFKA_DLQ_TOPIC_RETENTION_MS.
+func topicSpecs() []TopicSpec {
+	return []TopicSpec{
+		topicSpecFromEnv(KafkaTopic, "KAFKA_TOPIC"),
+		topicSpecFromEnv(KafkaRetryTopic, "KAFKA_RETRY_TOPIC"),
+		topicSpecFromEnv(KafkaDLQTopic, "KAFKA_DLQ_TOPIC"),
+		topicSpecFromEnv(KafkaEventsTopic, "KAFKA_EVENTS_TOPIC"),
+	}
+}
+
+func topicSpecFromEnv(name, prefix string) TopicSpec {
+	setting := func(key, fallback string) string {
+		return getEnv(prefix+"_"+key, getEnv("KAFKA_TOPIC_"+key, fallback))
+	}
+	// Counts that are not whole numbers, or are too small, fall back to the
+	// default rather than reach CreateTopics as 0.
+	count := func(key string, fallback, min int) int {
+		v := setting(key, strconv.Itoa(fallback))
+		n, err := strconv.Atoi(v)
+		if err != nil || n < min {
+			log.Printf("⚠️  Invalid %s %q for topic %s, using %d", strings.ToLower(key), v, name, fallback)
+			return fallback
+		}
+		return n
+	}
+	return TopicSpec{
+		Name:              name,
+		NumPartitions:     count("PARTITIONS", 3, 1),
+		ReplicationFactor: count("REPLICATION", 0, 0),
+		RetentionMs:       setting("RETENTION_MS", ""),
+		CleanupPolicy:     setting("CLEANUP_POLICY", ""),
+	}
+}
+
+// EnsureTopics creates any missing topic from topicSpecs and checks existing
+// ones against it. Mismatches are logged, and returned as an error when
+// KAFKA_TOPIC_STRICT is set.
+func EnsureTopics() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client := &kafka.Client{Addr: kafka.TCP(KafkaBrokers...), Timeout: 10 * time.Second}
+	specs := topicSpecs()
+
+	names := make([]string, len(specs))
+	for i, s := range specs {
+		names[i] = s.Name
+	}
+	meta, err := client.Metadata(ctx, &kafka.MetadataRequest{Topics: names})
+	if err != nil {
+		return fmt.Errorf("could not describe Kafka topics: %w", err)
+	}
+
+	existing := make(map[string]kafka.Topic, len(meta.Topics))
+	for _, t := range meta.Topics {
+		if t.Error == nil {
+			existing[t.Name] = t
+		} else if !errors.Is(t.Error, kafka.UnknownTopicOrPartition) {
+			return fmt.Errorf("could not describe topic %s: %w", t.Name, t.Error)
+		}
+	}
+
+	var missing []kafka.TopicConfig
+	var mismatches []string
+	for _, spec := range specs {
+		if topic, ok := existing[spec.Name]; ok {
+			found, err := describeTopic(ctx, client, topic)
+			if err != nil {
+				return err
+			}
+			mismatches = append(mismatches, spec.diff(found)...)
+			continue
+		}
+
+		// The default replication factor only applies to topics created
+		// here, so an existing topic is not compared against it.
+		if spec.ReplicationFactor <= 0 {
+			spec.ReplicationFactor = min(len(meta.Brokers), 3)
+		}
+		if spec.ReplicationFactor > len(meta.Brokers) {
+			return fmt.Errorf("topic %s wants replication factor %d but the cluster has %d broker(s)",
+				spec.Name, spec.ReplicationFactor, len(meta.Brokers))
+		}
+		missing = append(missing, spec.topicConfig())
+	}
+
+	if len(missing) > 0 {
+		resp, err := client.CreateTopics(ctx, &kafka.CreateTopicsRequest{Topics: missing})
+		if err != nil {
+			return fmt.Errorf("topic creation failed: %w", err)
+		}
+		for _, t := range missing {
+			if err := resp.Errors[t.Topic]; err != nil && !errors.Is(err, kafka.TopicAlreadyExists) {
+				return fmt.Errorf("topic creation failed for %s: %w", t.Topic, err)
+			}
+			log.Printf("✅ Kafka topic %s created (partitions=%d, replication=%d)",
+				t.Topic, t.NumPartitions, t.ReplicationFactor)
+		}
+	}
+
+	for _, m := range mismatches {
+		log.Printf("⚠️ Kafka topic mismatch: %s", m)
+	}
+	if len(mismatches) > 0 && TopicConfigStrict {
+		return fmt.Errorf("%d Kafka topic setting(s) differ from configuration", len(mismatches))
+	}
+	return nil
+}
+
+func (s TopicSpec) topicConfig() kafka.TopicConfig {
+	cfg := kafka.TopicConfig{
+		Topic:             s.Name,
+		NumPartitions:     s.NumPartitions,
+		ReplicationFactor: s.ReplicationFactor,
+	}
+	if s.RetentionMs != "" {
+		cfg.ConfigEntries = append(cfg.ConfigEntries, kafka.ConfigEntry{ConfigName: "retention.ms", ConfigValue: s.RetentionMs})
+	}
+	if s.CleanupPolicy != "" {
+		cfg.ConfigEntries = append(cfg.ConfigEntries, kafka.ConfigEntry{ConfigName: "cleanup.policy", ConfigValue: s.CleanupPolicy})
+	}
+	return cfg
+}
+
+// diff lists the settings where an existing topic differs from the spec.
+// Settings the spec leaves to the broker default are not compared.
+func (s TopicSpec) diff(found TopicSpec) []string {
+	var out []string
+	if s.NumPartitions > 0 && found.NumPartitions != s.NumPartitions {
+		out = append(out, fmt.Sprintf("%s has %d partitions, want %d", s.Name, found.NumPartitions, s.NumPartitions))
+	}
+	if s.ReplicationFactor > 0 && found.ReplicationFactor != s.ReplicationFactor {
+		out = append(out, fmt.Sprintf("%s has replication factor %d, want %d", s.Name, found.ReplicationFactor, s.ReplicationFactor))
+	}
+	if s.RetentionMs != "" && found.RetentionMs != s.RetentionMs {
+		out = append(out, fmt.Sprintf("%s has retention.ms=%s, want %s", s.Name, found.RetentionMs, s.RetentionMs))
+	}
+	if s.CleanupPolicy != "" && found.CleanupPolicy != s.CleanupPolicy {
+		out = append(out, fmt.Sprintf("%s has cleanup.policy=%s, want %s", s.Name, found.CleanupPolicy, s.CleanupPolicy))
+	}
+	return out
+}
+
+// describeTopic reads the current layout and retention settings of a topic.
+func describeTopic(ctx context.Context, client *kafka.Client, topic kafka.Topic) (TopicSpec, error) {
+	found := TopicSpec{Name: topic.Name, NumPartitions: len(topic.Partitions)}
+	if len(topic.Partitions) > 0 {
+		found.ReplicationFactor = len(topic.Partitions[0].Replicas)
+	}
+
+	resp, err := client.DescribeConfigs(ctx, &kafka.DescribeConfigsRequest{
+		Resources: []kafka.DescribeConfigRequestResource{{
+			ResourceType: kafka.ResourceTypeTopic,
+			ResourceName: topic.Name,
+			ConfigNames:  []string{"retention.ms", "cleanup.policy"},
+		}},
+	})
+	if err != nil {
+		return found, fmt.Errorf("could not describe configs of %s: %w", topic.Name, err)
+	}
+	for _, res := range resp.Resources {
+		if res.Error != nil {
+			return found, fmt.Errorf("could not describe configs of %s: %w", topic.Name, res.Error)
+		}
+		for _, e := range res.ConfigEntries {
+			switch e.ConfigName {
+			case "retention.ms":
+				found.RetentionMs = e.ConfigValue
+			case "cleanup.policy":
+				found.CleanupPolicy = strings.TrimSpace(e.ConfigValue)
+			}
+		}
+	}
+	return found, nil
+}
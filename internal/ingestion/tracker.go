@@ -2,10 +2,16 @@ package ingestion
 
 import (
 	"bufio"
+	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
 )
 
+var ProducerCheckpointLog = getEnv("PRODUCER_CHECKPOINT_LOG", "producer_checkpoints.log")
+
 func alreadyProcessed(filename string) bool {
 	file, err := os.Open(ProcessedMarker)
 	if err != nil {
@@ -31,3 +37,111 @@ func markAsProcessed(filename string) {
 	defer f.Close()
 	f.WriteString(filename + "\n")
 }
+
+// checkpoint records how far a file has been produced: the number of lines
+// acknowledged by Kafka, the byte offset just past the last of them and the
+// etag of the object they were read from.
+type checkpoint struct {
+	Line   int64
+	Offset int64
+	ETag   string
+}
+
+var (
+	checkpointsMu     sync.Mutex
+	checkpointsLoaded bool
+	checkpoints       map[string]checkpoint
+)
+
+// loadCheckpoint returns the latest checkpoint saved for marker, or a zero
+// checkpoint if the file has none yet.
+func loadCheckpoint(marker string) checkpoint {
+	checkpointsMu.Lock()
+	defer checkpointsMu.Unlock()
+	return readCheckpoints()[marker]
+}
+
+// saveCheckpoint records cp as the latest checkpoint of marker. The log is
+// rewritten with one entry per file still in progress, so it stays small
+// however many batches have been produced.
+func saveCheckpoint(marker string, cp checkpoint) {
+	checkpointsMu.Lock()
+	defer checkpointsMu.Unlock()
+	readCheckpoints()[marker] = cp
+	writeCheckpoints()
+}
+
+// clearCheckpoint drops the checkpoint of a file that has been produced in
+// full.
+func clearCheckpoint(marker string) {
+	checkpointsMu.Lock()
+	defer checkpointsMu.Unlock()
+	cps := readCheckpoints()
+	if _, ok := cps[marker]; !ok {
+		return
+	}
+	delete(cps, marker)
+	writeCheckpoints()
+}
+
+// readCheckpoints loads the checkpoint log on first use. checkpointsMu must
+// be held.
+func readCheckpoints() map[string]checkpoint {
+	if checkpointsLoaded {
+		return checkpoints
+	}
+	checkpointsLoaded = true
+	checkpoints = make(map[string]checkpoint)
+
+	file, err := os.Open(ProducerCheckpointLog)
+	if err != nil {
+		return checkpoints
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) != 4 {
+			continue
+		}
+		line, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		offset, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		checkpoints[fields[0]] = checkpoint{Line: line, Offset: offset, ETag: fields[3]}
+	}
+	return checkpoints
+}
+
+// writeCheckpoints replaces the checkpoint log with the current checkpoints.
+// It writes a temporary file and renames it over the log, so a crash leaves
+// either the old or the new log behind. checkpointsMu must be held.
+func writeCheckpoints() {
+	tmp := ProducerCheckpointLog + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		log.Printf("⚠️  Couldn't save checkpoint: %v", err)
+		return
+	}
+	w := bufio.NewWriter(f)
+	for marker, cp := range checkpoints {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", marker, cp.Line, cp.Offset, cp.ETag)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		log.Printf("⚠️  Couldn't save checkpoint: %v", err)
+		return
+	}
+	if err := f.Close(); err != nil {
+		log.Printf("⚠️  Couldn't save checkpoint: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, ProducerCheckpointLog); err != nil {
+		log.Printf("⚠️  Couldn't save checkpoint: %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+// Package langdetect identifies the language of review text offline. Scripts
+// used by a single language (Thai, Hangul, kana, ...) decide directly;
+// Latin-script text is scored against character n-gram profiles built at
+// startup from the sample texts embedded in corpus/.
+package langdetect
+
+import (
+	"embed"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+//go:embed corpus/*.txt
+var corpus embed.FS
+
+const (
+	// MinLetters is the shortest text, in letters, a guess is made for.
+	MinLetters = 8
+	// MinConfidence is the lowest confidence that is still reported.
+	MinConfidence = 0.45
+
+	maxGram = 3
+)
+
+// profile holds n-gram counts for one language.
+type profile struct {
+	lang   string
+	counts map[string]float64
+	total  float64
+}
+
+var profiles = loadProfiles()
+
+func loadProfiles() []profile {
+	entries, err := corpus.ReadDir("corpus")
+	if err != nil {
+		panic(err)
+	}
+	var out []profile
+	for _, e := range entries {
+		data, err := corpus.ReadFile(path.Join("corpus", e.Name()))
+		if err != nil {
+			panic(err)
+		}
+		p := profile{lang: strings.TrimSuffix(e.Name(), ".txt"), counts: map[string]float64{}}
+		for _, g := range grams(string(data)) {
+			p.counts[g]++
+			p.total++
+		}
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].lang < out[j].lang })
+	return out
+}
+
+// Languages lists the codes Detect can return.
+func Languages() []string {
+	langs := []string{"ar", "el", "he", "hi", "ja", "ko", "ru", "th", "zh"}
+	for _, p := range profiles {
+		langs = append(langs, p.lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Detect returns the ISO 639-1 code of the text's language and a confidence
+// between 0 and 1, or "" when the text is too short or no language is likely
+// enough.
+func Detect(text string) (string, float64) {
+	if lang, ok := detectScript(text); ok {
+		return lang, 1
+	}
+
+	gs := grams(text)
+	letters := 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	if letters < MinLetters || len(gs) == 0 {
+		return "", 0
+	}
+
+	// Naive Bayes over n-grams with add-one smoothing. The per-gram scores are
+	// scaled and normalized across languages into a confidence.
+	scores := make([]float64, len(profiles))
+	best := 0
+	for i, p := range profiles {
+		vocab := float64(len(p.counts))
+		for _, g := range gs {
+			scores[i] += math.Log((p.counts[g] + 1) / (p.total + vocab))
+		}
+		if scores[i] > scores[best] {
+			best = i
+		}
+	}
+	sum := 0.0
+	for _, s := range scores {
+		sum += math.Exp((s - scores[best]) / float64(len(gs)) * 10)
+	}
+	confidence := 1 / sum
+	if confidence < MinConfidence {
+		return "", confidence
+	}
+	return profiles[best].lang, confidence
+}
+
+// detectScript recognizes text written mostly in a script that belongs to a
+// single language.
+func detectScript(text string) (string, bool) {
+	counts := map[string]int{}
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.Is(unicode.Hangul, r):
+			counts["ko"]++
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			counts["ja"]++
+		case unicode.Is(unicode.Han, r):
+			counts["zh"]++
+		case unicode.Is(unicode.Thai, r):
+			counts["th"]++
+		case unicode.Is(unicode.Cyrillic, r):
+			counts["ru"]++
+		case unicode.Is(unicode.Arabic, r):
+			counts["ar"]++
+		case unicode.Is(unicode.Hebrew, r):
+			counts["he"]++
+		case unicode.Is(unicode.Greek, r):
+			counts["el"]++
+		case unicode.Is(unicode.Devanagari, r):
+			counts["hi"]++
+		}
+	}
+	if letters == 0 {
+		return "", false
+	}
+	// Japanese mixes kanji with kana; any kana marks it as Japanese.
+	if counts["ja"] > 0 && counts["ja"]+counts["zh"] > letters/2 {
+		return "ja", true
+	}
+	for lang, n := range counts {
+		if n > letters/2 {
+			return lang, true
+		}
+	}
+	return "", false
+}
+
+// grams splits text into lowercased words and returns their 1- to 3-grams,
+// with word boundaries marked by spaces.
+func grams(text string) []string {
+	var out []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		runes := []rune(" " + word + " ")
+		for n := 1; n <= maxGram; n++ {
+			for i := 0; i+n <= len(runes); i++ {
+				if g := string(runes[i : i+n]); g != " " {
+					out = append(out, g)
+				}
+			}
+		}
+	}
+	return out
+}
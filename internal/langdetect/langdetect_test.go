@@ -0,0 +1,67 @@
+package langdetect
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		// Short reviews, a few words each.
+		{"short english", "Very clean room", "en"},
+		{"short german", "Sehr sauber und ruhig", "de"},
+		{"short french", "Très bon séjour", "fr"},
+		{"short spanish", "Muy buen hotel", "es"},
+		{"short vietnamese", "Khách sạn rất sạch sẽ", "vi"},
+		{"short indonesian", "Hotel bagus dan bersih", "id"},
+		{"short turkish", "Çok temiz otel", "tr"},
+		{"short polish", "Bardzo czysty hotel", "pl"},
+
+		// Too short to guess.
+		{"one word", "Nice", ""},
+		{"under min letters", "Sạch", ""},
+		{"no letters", "12345 !!!", ""},
+		{"emoji", "Staff 👍👍👍", ""},
+
+		// Single-language scripts decide directly, however short.
+		{"japanese", "素晴らしいホテル", "ja"},
+		{"chinese", "很好的酒店", "zh"},
+		{"korean", "좋은 호텔", "ko"},
+		{"russian", "Отличный отель", "ru"},
+
+		// Mixed texts go to the language most of the text is in.
+		{"japanese with latin", "WiFiが速くて部屋もきれいでした", "ja"},
+		{"vietnamese with english words", "Phòng sạch, wifi mạnh, staff nhiệt tình", "vi"},
+		{"english with french phrase", "Lovely stay, the view from the room was amazing, merci beaucoup", "en"},
+		{"french with english phrase", "Le petit déjeuner était excellent, very nice staff too", "fr"},
+		{"spanish with english word", "El personal fue muy amable y la habitación estaba limpia, thanks", "es"},
+		{"english with emoji", "Room clean 😀😀", "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, confidence := Detect(tt.text)
+			if got != tt.want {
+				t.Errorf("Detect(%q) = %q (%.2f), want %q", tt.text, got, confidence, tt.want)
+			}
+			if got != "" && confidence < MinConfidence {
+				t.Errorf("Detect(%q) reported %q below MinConfidence (%.2f)", tt.text, got, confidence)
+			}
+		})
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	langs := Languages()
+	if !slices.IsSorted(langs) {
+		t.Errorf("Languages() = %v, want sorted", langs)
+	}
+	for _, lang := range []string{"en", "vi", "ja", "th"} {
+		if !slices.Contains(langs, lang) {
+			t.Errorf("Languages() = %v, missing %s", langs, lang)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Package moderation changes the moderation status of reviews in bulk and
+// records each change in the audit trail.
+package moderation
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"review-system/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrInvalid = errors.New("invalid request")
+
+// MaxBatch is the most reviews one call may moderate.
+const MaxBatch = 500
+
+// Result reports what a bulk change did to each requested review.
+type Result struct {
+	Updated   []uint `json:"updated"`
+	Unchanged []uint `json:"unchanged"` // already in the requested status
+	NotFound  []uint `json:"not_found"`
+}
+
+// Apply moves the given reviews to status on behalf of actor. A reason is
+// required for every status but visible. Reviews that enter or leave the
+// visible status are taken into or out of their hotels' rating summaries
+// and can change which review is their duplicate cluster's primary. They
+// are published to the events topic as deleted or, when shown again, as
+// updated, in the same transaction, and the reviews they share a suspicious
+// pattern with are scored again.
+func Apply(db *gorm.DB, reviewIDs []uint, status, reason, actor string) (Result, error) {
+	result := Result{Updated: []uint{}, Unchanged: []uint{}, NotFound: []uint{}}
+	if !slices.Contains(models.ModerationStatuses, status) {
+		return result, fmt.Errorf("status must be one of %v: %w", models.ModerationStatuses, ErrInvalid)
+	}
+	if reason == "" && status != models.ModerationVisible {
+		return result, fmt.Errorf("a reason is required to make reviews %s: %w", status, ErrInvalid)
+	}
+	if len(reviewIDs) == 0 || len(reviewIDs) > MaxBatch {
+		return result, fmt.Errorf("between 1 and %d review ids are required: %w", MaxBatch, ErrInvalid)
+	}
+	ids := slices.Clone(reviewIDs)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var reviews []models.Review
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", ids).Order("id").Find(&reviews).Error; err != nil {
+			return err
+		}
+		found := make(map[uint]bool, len(reviews))
+		var hotels, clusters []uint
+		var flipped []models.Review
+		now := time.Now()
+		for _, r := range reviews {
+			found[r.ID] = true
+			if r.ModerationStatus == status {
+				result.Unchanged = append(result.Unchanged, r.ID)
+				continue
+			}
+			if err := tx.Model(&models.Review{}).Where("id = ?", r.ID).Updates(map[string]interface{}{
+				"moderation_status": status,
+				"moderation_reason": reason,
+				"moderated_by":      actor,
+				"moderated_at":      now,
+			}).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&models.ModerationAction{
+				ReviewID:   r.ID,
+				FromStatus: r.ModerationStatus,
+				ToStatus:   status,
+				Reason:     reason,
+				Actor:      actor,
+				CreatedAt:  now,
+			}).Error; err != nil {
+				return err
+			}
+			result.Updated = append(result.Updated, r.ID)
+			if r.Visible() == (status == models.ModerationVisible) {
+				continue
+			}
+			op := "delete"
+			if status == models.ModerationVisible {
+				op = "update"
+			}
+			if err := models.EnqueueStoredReviewEvent(tx, op, r); err != nil {
+				return err
+			}
+			flipped = append(flipped, r)
+			if !slices.Contains(hotels, r.HotelID) {
+				hotels = append(hotels, r.HotelID)
+			}
+			if r.DuplicateClusterID != nil && !slices.Contains(clusters, *r.DuplicateClusterID) {
+				clusters = append(clusters, *r.DuplicateClusterID)
+			}
+		}
+		for _, id := range ids {
+			if !found[id] {
+				result.NotFound = append(result.NotFound, id)
+			}
+		}
+		// A cluster is counted once through its primary, which should be a
+		// review that is still shown.
+		for _, id := range clusters {
+			if err := models.ElectPrimary(tx, id); err != nil {
+				return err
+			}
+		}
+		// Patterns such as a burst or a template only count shown reviews,
+		// so the reviews sharing one with a changed review are scored again.
+		for i := range flipped {
+			var err error
+			if status == models.ModerationVisible {
+				err = models.ScoreSuspicion(tx, &flipped[i])
+			} else {
+				err = models.RescoreNeighbours(tx, flipped[i])
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if len(hotels) == 0 {
+			return nil
+		}
+		return models.RecomputeHotelSummaries(tx, hotels...)
+	})
+	return result, err
+}
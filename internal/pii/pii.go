@@ -0,0 +1,185 @@
+// Package pii finds personal data guests write into reviews (email
+// addresses, phone numbers, card numbers, room numbers, staff names and any
+// configured pattern) and replaces it with a placeholder such as [EMAIL].
+package pii
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Kinds of personal data the built-in detectors find.
+const (
+	Email = "email"
+	Phone = "phone"
+	Card  = "card"
+	Room  = "room"
+	Name  = "name"
+)
+
+// Detector finds one kind of personal data.
+type Detector struct {
+	Kind    string
+	Pattern *regexp.Regexp
+	// Group is the submatch that is redacted, 0 for the whole match. It lets
+	// "room 1204" keep the word "room".
+	Group int
+	// Valid, if set, rejects matches that only look like the kind, such as
+	// digit runs that fail the card checksum.
+	Valid func(match string) bool
+}
+
+// DefaultDetectors are the detectors every Redactor starts with.
+var DefaultDetectors = []Detector{
+	{Kind: Email, Pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)},
+	{Kind: Card, Pattern: regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`), Valid: luhn},
+	{Kind: Phone, Pattern: phonePattern, Valid: phoneLike},
+	{Kind: Room, Pattern: regexp.MustCompile(`(?i)\b(?:room|rm|phòng|chambre|zimmer|habitación|kamar)\.?\s*(?:no\.?|number|#)?\s*(\d{2,5}[A-Za-z]?)\b`), Group: 1},
+	{Kind: Name, Pattern: regexp.MustCompile(`\b(?:Mr|Mrs|Ms|Miss|Dr|Mr\.|Mrs\.|Ms\.|Dr\.|Anh|Chị|Bapak|Ibu|Pak|Bu)\s+((?:[A-Z][\p{L}'\-]+)(?:\s+[A-Z][\p{L}'\-]+){0,2})`), Group: 1},
+	{Kind: Name, Pattern: regexp.MustCompile(`(?i:receptionist|manager|waiter|waitress|concierge|driver|housekeeper|staff member|bellboy)\s+((?:[A-Z][\p{L}'\-]+)(?:\s+[A-Z][\p{L}'\-]+){1,2})`), Group: 1},
+}
+
+// Finding is one piece of personal data found in a text.
+type Finding struct {
+	Kind  string
+	Start int
+	End   int
+}
+
+// Redactor replaces personal data in text.
+type Redactor struct {
+	detectors []Detector
+}
+
+// NewRedactor returns a Redactor with the default detectors and the extra
+// patterns in spec, given as "kind=regexp" entries separated by ";", e.g.
+// "staff_name=(?i)\bJohn Smith\b;booking_ref=\bBK\d{8}\b". Patterns cannot
+// contain ";".
+func NewRedactor(spec string) (*Redactor, error) {
+	r := &Redactor{detectors: append([]Detector(nil), DefaultDetectors...)}
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kind, expr, ok := strings.Cut(entry, "=")
+		kind = strings.ToLower(strings.TrimSpace(kind))
+		if !ok || kind == "" {
+			return nil, fmt.Errorf("invalid pattern %q, want kind=regexp", entry)
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern for %s: %w", kind, err)
+		}
+		r.detectors = append(r.detectors, Detector{Kind: kind, Pattern: re})
+	}
+	return r, nil
+}
+
+// Find returns the personal data in text, in order and without overlaps.
+// When two findings overlap the one found by the earlier detector wins.
+func (r *Redactor) Find(text string) []Finding {
+	var found []Finding
+	for _, d := range r.detectors {
+		for _, m := range d.Pattern.FindAllStringSubmatchIndex(text, -1) {
+			start, end := m[2*d.Group], m[2*d.Group+1]
+			if start < 0 || (d.Valid != nil && !d.Valid(text[start:end])) {
+				continue
+			}
+			if overlaps(found, start, end) {
+				continue
+			}
+			found = append(found, Finding{Kind: d.Kind, Start: start, End: end})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Start < found[j].Start })
+	return found
+}
+
+// Redact replaces every finding with a placeholder naming its kind and
+// returns the sorted, distinct kinds it replaced.
+func (r *Redactor) Redact(text string) (string, []string) {
+	found := r.Find(text)
+	if len(found) == 0 {
+		return text, nil
+	}
+
+	var b strings.Builder
+	kinds := map[string]bool{}
+	last := 0
+	for _, f := range found {
+		b.WriteString(text[last:f.Start])
+		b.WriteString("[" + strings.ToUpper(f.Kind) + "]")
+		kinds[f.Kind] = true
+		last = f.End
+	}
+	b.WriteString(text[last:])
+
+	out := make([]string, 0, len(kinds))
+	for k := range kinds {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return b.String(), out
+}
+
+func overlaps(found []Finding, start, end int) bool {
+	for _, f := range found {
+		if start < f.End && f.Start < end {
+			return true
+		}
+	}
+	return false
+}
+
+// luhn reports whether the digits in s pass the card number checksum.
+func luhn(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && n <= 19 && sum%10 == 0
+}
+
+// phonePattern only matches digits written the way phone numbers are, so
+// runs of years, counts or distances such as "2023 2024 2025" are left
+// alone. It takes numbers with an international prefix (+84, 0084), an area
+// code in parentheses, a national trunk 0 (0912 345 678) or the 3-3-4
+// grouping of North American numbers (555-123-4567).
+var phonePattern = regexp.MustCompile(`(?:\+|\b00)\d{1,3}[ .\-]?(?:\(\d{1,4}\)[ .\-]?)?\d{1,4}(?:[ .\-]?\d{2,4}){1,4}\b` +
+	`|\(\d{2,4}\)[ .\-]?\d{3,4}[ .\-]?\d{3,4}\b` +
+	`|\b0\d{1,3}[ .\-]?\d{3,4}[ .\-]?\d{3,4}\b` +
+	`|\b\d{3}-\d{3}-\d{4}\b|\b\d{3}\.\d{3}\.\d{4}\b`)
+
+var (
+	datePattern   = regexp.MustCompile(`^(?:\d{4}[./\-]\d{1,2}[./\-]\d{1,2}|\d{1,2}[./\-]\d{1,2}[./\-]\d{4})$`)
+	amountPattern = regexp.MustCompile(`^\d{1,3}(?:[.,]\d{3})+$`)
+)
+
+// phoneLike keeps matches with 8 to 15 digits that are not dates or amounts
+// written with thousands separators.
+func phoneLike(s string) bool {
+	if datePattern.MatchString(s) || amountPattern.MatchString(s) {
+		return false
+	}
+	n := 0
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			n++
+		}
+	}
+	return n >= 8 && n <= 15
+}
@@ -0,0 +1,74 @@
+package pii
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	r, err := NewRedactor("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		text  string
+		want  string
+		kinds []string
+	}{
+		// Phone numbers in the shapes guests write them.
+		{"international", "Call me on +84 912 345 678 anytime", "Call me on [PHONE] anytime", []string{Phone}},
+		{"international unseparated", "whatsapp +84912345678", "whatsapp [PHONE]", []string{Phone}},
+		{"international 00 prefix", "ring 0084 28 3823 4999", "ring [PHONE]", []string{Phone}},
+		{"area code in parentheses", "front desk (028) 3823 4999", "front desk [PHONE]", []string{Phone}},
+		{"national trunk 0", "my number is 0912 345 678.", "my number is [PHONE].", []string{Phone}},
+		{"national dashed", "text 090-123-4567 please", "text [PHONE] please", []string{Phone}},
+		{"north american dashed", "call 555-123-4567", "call [PHONE]", []string{Phone}},
+		{"north american dotted", "call 555.123.4567", "call [PHONE]", []string{Phone}},
+
+		// Numbers that only look like phone numbers.
+		{"years", "We stayed in 2023 2024 2025", "We stayed in 2023 2024 2025", nil},
+		{"repeated counts", "10 10 10 10 would come again", "10 10 10 10 would come again", nil},
+		{"distances", "200 300 meters away 1000 2000", "200 300 meters away 1000 2000", nil},
+		{"year range", "renovated 2019-2020", "renovated 2019-2020", nil},
+		{"date", "checked in 2025-04-19", "checked in 2025-04-19", nil},
+		{"amount", "paid 1,250,000 VND", "paid 1,250,000 VND", nil},
+		{"too few digits", "+84 123", "+84 123", nil},
+
+		// Other kinds.
+		{"email", "write to jane.doe@example.com", "write to [EMAIL]", []string{Email}},
+		{"card", "charged 4111 1111 1111 1111 twice", "charged [CARD] twice", []string{Card}},
+		{"card failing luhn", "ref 4111 1111 1111 1112", "ref 4111 1111 1111 1112", nil},
+		{"room", "Room 1204 was noisy", "Room [ROOM] was noisy", []string{Room}},
+		{"titled name", "thanks to Mr John Smith", "thanks to Mr [NAME]", []string{Name}},
+		{"staff name", "receptionist Linh Nguyen was great", "receptionist [NAME] was great", []string{Name}},
+		{"several kinds", "room 305, +84 912 345 678", "room [ROOM], [PHONE]", []string{Phone, Room}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, kinds := r.Redact(tt.text)
+			if got != tt.want {
+				t.Errorf("Redact(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+			if !slices.Equal(kinds, tt.kinds) {
+				t.Errorf("Redact(%q) kinds = %v, want %v", tt.text, kinds, tt.kinds)
+			}
+		})
+	}
+}
+
+func TestNewRedactorPatterns(t *testing.T) {
+	r, err := NewRedactor(`booking_ref=\bBK\d{8}\b`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, kinds := r.Redact("booking BK12345678 confirmed"); got != "booking [BOOKING_REF] confirmed" || !slices.Equal(kinds, []string{"booking_ref"}) {
+		t.Errorf("got %q %v", got, kinds)
+	}
+
+	for _, spec := range []string{"=abc", "nokind", "bad=("} {
+		if _, err := NewRedactor(spec); err == nil {
+			t.Errorf("NewRedactor(%q) accepted an invalid pattern", spec)
+		}
+	}
+}
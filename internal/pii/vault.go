@@ -0,0 +1,60 @@
+package pii
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// Policies for the original text of a review that had personal data
+// redacted.
+const (
+	PolicyDrop    = "drop"    // keep only the redacted text
+	PolicyEncrypt = "encrypt" // also keep the original, encrypted
+)
+
+// Vault encrypts original review text with AES-256-GCM.
+type Vault struct {
+	aead cipher.AEAD
+}
+
+// NewVault returns a Vault for a base64-encoded 32-byte key.
+func NewVault(key string) (*Vault, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("key is not base64: %w", err)
+	}
+	if len(raw) != 32 {
+		return nil, fmt.Errorf("key is %d bytes, want 32", len(raw))
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Vault{aead: aead}, nil
+}
+
+// Seal encrypts plaintext and returns the nonce followed by the ciphertext.
+func (v *Vault) Seal(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, v.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return v.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Open decrypts a value written by Seal.
+func (v *Vault) Open(sealed []byte) ([]byte, error) {
+	n := v.aead.NonceSize()
+	if len(sealed) < n {
+		return nil, errors.New("sealed value too short")
+	}
+	return v.aead.Open(nil, sealed[:n], sealed[n:], nil)
+}
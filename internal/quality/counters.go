@@ -0,0 +1,54 @@
+package quality
+
+import (
+	"time"
+
+	"review-system/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RecordViolations adds a record's violations to today's per-rule counters
+// and the record itself to today's counter for the action taken on it.
+func RecordViolations(db *gorm.DB, platform string, res Result) error {
+	now := time.Now().UTC()
+	day := now.Truncate(24 * time.Hour)
+	for _, v := range res.Violations {
+		row := models.QualityViolationCount{
+			Day:      day,
+			Platform: platform,
+			Rule:     v.Rule,
+			Action:   v.Action.String(),
+			Count:    1,
+			LastSeen: now,
+		}
+		if err := db.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "day"}, {Name: "platform"}, {Name: "rule"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"count":     gorm.Expr("quality_violation_counts.count + 1"),
+				"action":    row.Action,
+				"last_seen": now,
+			}),
+		}).Create(&row).Error; err != nil {
+			return err
+		}
+	}
+	if res.Action == Pass {
+		return nil
+	}
+	outcome := models.QualityOutcomeCount{
+		Day:      day,
+		Platform: platform,
+		Action:   res.Action.String(),
+		Count:    1,
+		LastSeen: now,
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "day"}, {Name: "platform"}, {Name: "action"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"count":     gorm.Expr("quality_outcome_counts.count + 1"),
+			"last_seen": now,
+		}),
+	}).Create(&outcome).Error
+}
@@ -0,0 +1,54 @@
+package quality
+
+// Violation is a rule a record broke and the action taken for it.
+type Violation struct {
+	Rule   string
+	Action Action
+}
+
+// Result is the outcome of checking one record.
+type Result struct {
+	// Action is the most severe action among the violations, or Pass.
+	Action     Action
+	Violations []Violation
+}
+
+// Flags returns the names of every rule the record broke, for storing on a
+// review that is accepted.
+func (r Result) Flags() []string {
+	flags := make([]string, 0, len(r.Violations))
+	for _, v := range r.Violations {
+		flags = append(flags, v.Rule)
+	}
+	return flags
+}
+
+// Engine evaluates records against a rule set.
+type Engine struct {
+	rules []Rule
+}
+
+func NewEngine(rules []Rule) *Engine {
+	return &Engine{rules: rules}
+}
+
+// Rules returns the engine's rule set.
+func (e *Engine) Rules() []Rule {
+	return e.rules
+}
+
+// Evaluate checks rec against every rule that is enabled for its platform.
+func (e *Engine) Evaluate(rec Record) Result {
+	var res Result
+	for _, rule := range e.rules {
+		action := rule.ActionFor(rec.Platform)
+		if action == Pass || !rule.Violated(rec) {
+			continue
+		}
+		res.Violations = append(res.Violations, Violation{Rule: rule.Name, Action: action})
+		if action > res.Action {
+			res.Action = action
+		}
+	}
+	return res
+}
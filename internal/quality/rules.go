@@ -0,0 +1,176 @@
+// Package quality checks incoming review records against a declarative rule
+// set. Each rule names a condition and what to do with a record that meets
+// it: flag it and store it anyway, quarantine it for a person to look at, or
+// reject it outright. Actions can be changed per platform.
+package quality
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Action is what happens to a record that violates a rule. Actions are
+// ordered by severity; a record gets the most severe action of its
+// violations.
+type Action int
+
+const (
+	Pass Action = iota
+	Flag
+	Quarantine
+	Reject
+)
+
+var actionNames = map[Action]string{Pass: "off", Flag: "flag", Quarantine: "quarantine", Reject: "reject"}
+
+func (a Action) String() string {
+	return actionNames[a]
+}
+
+// ParseAction reads an action name. "off" disables a rule.
+func ParseAction(name string) (Action, error) {
+	for a, n := range actionNames {
+		if strings.EqualFold(name, n) {
+			return a, nil
+		}
+	}
+	return Pass, fmt.Errorf("unknown action %q", name)
+}
+
+// Record is the part of a review the rules look at.
+type Record struct {
+	Platform      string
+	HotelReviewID int64
+	// HotelReviewIDErr is set when hotelReviewId was missing or not an integer.
+	HotelReviewIDErr error
+	Rating           float32
+	ScaleMin         float32
+	ScaleMax         float32
+	ReviewDate       time.Time
+	// ReviewDateErr is set when reviewDate was present but unparseable.
+	ReviewDateErr error
+	Title         string
+	Text          string
+}
+
+// Rule is a named check. Violated reports whether a record breaks it.
+type Rule struct {
+	Name        string
+	Description string
+	Action      Action
+	// Platforms overrides Action for the platforms listed, keyed by
+	// lowercased platform name.
+	Platforms map[string]Action
+	Violated  func(Record) bool
+}
+
+// ActionFor returns the rule's action for a platform.
+func (r Rule) ActionFor(platform string) Action {
+	if a, ok := r.Platforms[strings.ToLower(platform)]; ok {
+		return a
+	}
+	return r.Action
+}
+
+// FutureDateTolerance allows for providers stamping reviews in a timezone
+// ahead of ours.
+const FutureDateTolerance = 24 * time.Hour
+
+// DefaultRules is the rule set used unless overridden with LoadRules.
+var DefaultRules = []Rule{
+	{
+		Name:        "invalid_review_id",
+		Description: "hotelReviewId is missing, not an integer, or not positive",
+		Action:      Reject,
+		Violated: func(r Record) bool {
+			return r.HotelReviewIDErr != nil || r.HotelReviewID <= 0
+		},
+	},
+	{
+		Name:        "rating_out_of_scale",
+		Description: "rating is outside the platform's rating scale",
+		Action:      Quarantine,
+		Violated: func(r Record) bool {
+			return r.ScaleMax > r.ScaleMin && (r.Rating < r.ScaleMin || r.Rating > r.ScaleMax)
+		},
+	},
+	{
+		Name:        "future_review_date",
+		Description: "reviewDate is in the future",
+		Action:      Quarantine,
+		Violated: func(r Record) bool {
+			return r.ReviewDate.After(time.Now().Add(FutureDateTolerance))
+		},
+	},
+	{
+		Name:        "missing_review_date",
+		Description: "reviewDate is missing",
+		Action:      Flag,
+		Violated: func(r Record) bool {
+			return r.ReviewDate.IsZero() && r.ReviewDateErr == nil
+		},
+	},
+	{
+		Name:        "unparseable_review_date",
+		Description: "reviewDate is in no known format; the review is stored without a date",
+		Action:      Flag,
+		Violated: func(r Record) bool {
+			return r.ReviewDateErr != nil
+		},
+	},
+	{
+		Name:        "empty_text",
+		Description: "both reviewTitle and reviewComments are empty",
+		Action:      Flag,
+		Violated: func(r Record) bool {
+			return strings.TrimSpace(r.Title) == "" && strings.TrimSpace(r.Text) == ""
+		},
+	},
+}
+
+// LoadRules returns DefaultRules with actions overridden by spec, a
+// comma-separated list of rule=action or platform/rule=action entries, e.g.
+// "empty_text=reject,expedia/rating_out_of_scale=flag,future_review_date=off".
+func LoadRules(spec string) ([]Rule, error) {
+	rules := make([]Rule, len(DefaultRules))
+	index := make(map[string]int, len(DefaultRules))
+	for i, r := range DefaultRules {
+		r.Platforms = make(map[string]Action, len(r.Platforms))
+		for p, a := range DefaultRules[i].Platforms {
+			r.Platforms[p] = a
+		}
+		rules[i] = r
+		index[r.Name] = i
+	}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, actionName, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rule override %q, want rule=action", entry)
+		}
+		action, err := ParseAction(strings.TrimSpace(actionName))
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule override %q: %w", entry, err)
+		}
+
+		platform, name, scoped := strings.Cut(strings.TrimSpace(key), "/")
+		if !scoped {
+			name = platform
+		}
+		i, known := index[name]
+		if !known {
+			return nil, fmt.Errorf("invalid rule override %q: unknown rule %q", entry, name)
+		}
+		if scoped {
+			rules[i].Platforms[strings.ToLower(platform)] = action
+		} else {
+			rules[i].Action = action
+		}
+	}
+	return rules, nil
+}
@@ -0,0 +1,169 @@
+package sentiment
+
+// Built-in lexicons, tuned for hotel reviews: "noisy" and "small" are
+// complaints here even if they are neutral elsewhere.
+func init() {
+	Register("en", english)
+	Register("fr", french)
+	Register("es", spanish)
+	Register("de", german)
+	Register("vi", vietnamese)
+	Register("id", indonesian)
+}
+
+func set(words ...string) map[string]bool {
+	m := make(map[string]bool, len(words))
+	for _, w := range words {
+		m[w] = true
+	}
+	return m
+}
+
+var english = &Lexicon{
+	Words: map[string]float64{
+		"excellent": 3.2, "amazing": 3, "outstanding": 3.2, "perfect": 3, "fantastic": 3,
+		"wonderful": 3, "superb": 3, "awesome": 3, "exceptional": 3.2, "great": 2.5,
+		"lovely": 2.5, "beautiful": 2.5, "love": 2.8, "loved": 2.8, "best": 2.8,
+		"good": 1.8, "nice": 1.8, "clean": 1.6, "spotless": 2.5, "comfortable": 1.8,
+		"comfy": 1.8, "friendly": 2, "helpful": 2, "welcoming": 2, "polite": 1.5,
+		"attentive": 1.8, "quiet": 1.2, "spacious": 1.5, "convenient": 1.5,
+		"recommend": 2, "recommended": 2, "enjoyed": 2, "pleasant": 1.8, "delicious": 2.5,
+		"tasty": 2, "fresh": 1.2, "cozy": 1.5, "modern": 1, "value": 0.8, "worth": 1.5,
+		"happy": 2, "satisfied": 1.8, "well maintained": 1.8, "okay": 0.9, "ok": 0.9,
+		"fine": 0.8, "decent": 1, "smooth": 1.2, "fast": 1, "quick": 1, "efficient": 1.5,
+		"easy": 1, "central": 1, "affordable": 1.2, "cheap": 0.5,
+
+		"bad": -2.5, "terrible": -3.2, "horrible": -3.2, "awful": -3.2, "worst": -3.4,
+		"poor": -2.2, "dirty": -2.5, "filthy": -3, "disgusting": -3.2, "rude": -2.8,
+		"unfriendly": -2.2, "unhelpful": -2.2, "noisy": -1.8, "noise": -1.2, "loud": -1.5,
+		"smelly": -2.2, "smell": -1, "stained": -2, "broken": -2, "old": -0.8,
+		"outdated": -1.5, "tiny": -1.2, "small": -0.6, "cramped": -1.5, "uncomfortable": -2,
+		"slow": -1.5, "expensive": -1.2, "overpriced": -2.2, "disappointing": -2.5,
+		"disappointed": -2.5, "disappointment": -2.5, "mediocre": -1.5, "average": -0.3,
+		"problem": -1.5, "issue": -1.2, "issues": -1.2, "complaint": -1.5, "bugs": -2.5,
+		"cockroach": -3, "cockroaches": -3, "mold": -2.5, "mould": -2.5, "avoid": -2.5,
+		"never again": -3, "waste": -2.5, "unacceptable": -3, "scam": -3.2, "cold": -0.8,
+		"crowded": -1, "hate": -3, "hated": -3, "wait": -0.8, "waited": -1,
+
+		"not bad": 1.2, "no complaints": 2, "no problem": 1.2, "no problems": 1.2,
+		"could be better": -1.5, "could have been better": -1.5, "not working": -2,
+		"didn't work": -2, "did not work": -2, "too long": -1.5, "took ages": -1.8,
+		"not worth": -2, "not clean": -2.2, "not recommend": -2.4, "would not recommend": -2.4,
+		"not great": -1.2, "not good": -1.6, "not much": -0.5,
+	},
+	Negators: set("not", "no", "never", "none", "nothing", "nobody", "neither", "nor",
+		"without", "hardly", "barely", "cannot", "cant", "dont", "didnt", "wasnt", "isnt", "wont"),
+	Boosters: map[string]float64{
+		"very": 1.3, "really": 1.3, "extremely": 1.5, "super": 1.4, "so": 1.2, "too": 1.2,
+		"incredibly": 1.5, "absolutely": 1.5, "totally": 1.3, "quite": 1.1, "most": 1.2,
+		"slightly": 0.7, "somewhat": 0.7, "bit": 0.8, "little": 0.8, "fairly": 0.9,
+	},
+	Contrasts: set("but", "however", "although", "though", "yet", "except"),
+}
+
+var french = &Lexicon{
+	Words: map[string]float64{
+		"excellent": 3.2, "excellente": 3.2, "parfait": 3, "parfaite": 3, "magnifique": 3,
+		"superbe": 3, "génial": 3, "formidable": 3, "merveilleux": 3, "super": 2.5,
+		"très bien": 2.5, "bien": 1.5, "bon": 1.8, "bonne": 1.8, "agréable": 1.8,
+		"propre": 1.6, "confortable": 1.8, "calme": 1.2, "sympathique": 2, "sympa": 2,
+		"accueillant": 2, "accueillante": 2, "serviable": 2, "aimable": 2, "chaleureux": 2,
+		"spacieux": 1.5, "spacieuse": 1.5, "délicieux": 2.5, "recommande": 2, "bien situé": 1.8,
+		"correct": 0.8, "correcte": 0.8,
+
+		"mauvais": -2.5, "mauvaise": -2.5, "horrible": -3.2, "affreux": -3.2, "nul": -2.8,
+		"sale": -2.5, "bruyant": -1.8, "bruyante": -1.8, "bruit": -1.2, "impoli": -2.8,
+		"désagréable": -2.2, "déçu": -2.5, "déçue": -2.5, "décevant": -2.5, "cher": -1.2,
+		"petit": -0.6, "petite": -0.6, "vieux": -0.8, "vétuste": -1.5, "cassé": -2,
+		"odeur": -1, "problème": -1.5, "lent": -1.5, "à éviter": -3, "moyen": -0.3,
+		"pas terrible": -1.5,
+	},
+	Negators: set("ne", "pas", "jamais", "rien", "aucun", "aucune", "sans", "ni"),
+	Boosters: map[string]float64{
+		"très": 1.3, "vraiment": 1.3, "trop": 1.2, "extrêmement": 1.5, "super": 1.4,
+		"assez": 1.1, "peu": 0.7,
+	},
+	Contrasts: set("mais", "cependant", "pourtant", "sauf"),
+}
+
+var spanish = &Lexicon{
+	Words: map[string]float64{
+		"excelente": 3.2, "perfecto": 3, "perfecta": 3, "increíble": 3, "maravilloso": 3,
+		"fantástico": 3, "genial": 2.8, "estupendo": 2.8, "bueno": 1.8, "buena": 1.8,
+		"bien": 1.5, "limpio": 1.6, "limpia": 1.6, "cómodo": 1.8, "cómoda": 1.8,
+		"amable": 2, "amables": 2, "simpático": 2, "atento": 1.8, "tranquilo": 1.2,
+		"espacioso": 1.5, "delicioso": 2.5, "recomiendo": 2, "recomendable": 2, "agradable": 1.8,
+		"bien ubicado": 1.8, "correcto": 0.8,
+
+		"malo": -2.5, "mala": -2.5, "terrible": -3.2, "horrible": -3.2, "pésimo": -3.4,
+		"sucio": -2.5, "sucia": -2.5, "ruidoso": -1.8, "ruidosa": -1.8, "ruido": -1.2,
+		"maleducado": -2.8, "antipático": -2.2, "decepcionante": -2.5, "decepcionado": -2.5,
+		"caro": -1.2, "pequeño": -0.6, "viejo": -0.8, "roto": -2, "olor": -1,
+		"problema": -1.5, "lento": -1.5, "regular": -0.3,
+	},
+	Negators: set("no", "nunca", "nada", "ningún", "ninguna", "sin", "ni", "jamás"),
+	Boosters: map[string]float64{
+		"muy": 1.3, "realmente": 1.3, "demasiado": 1.2, "súper": 1.4, "bastante": 1.1,
+		"extremadamente": 1.5, "poco": 0.7,
+	},
+	Contrasts: set("pero", "embargo", "aunque", "excepto"),
+}
+
+var german = &Lexicon{
+	Words: map[string]float64{
+		"ausgezeichnet": 3.2, "hervorragend": 3.2, "perfekt": 3, "wunderbar": 3, "toll": 2.5,
+		"super": 2.5, "sehr gut": 2.5, "gut": 1.8, "schön": 2, "sauber": 1.6,
+		"bequem": 1.8, "gemütlich": 1.5, "freundlich": 2, "hilfsbereit": 2, "ruhig": 1.2,
+		"geräumig": 1.5, "lecker": 2.5, "empfehlen": 2, "empfehlenswert": 2, "angenehm": 1.8,
+		"in ordnung": 0.8, "ok": 0.8,
+
+		"schlecht": -2.5, "schrecklich": -3.2, "furchtbar": -3.2, "katastrophal": -3.4,
+		"schmutzig": -2.5, "dreckig": -2.5, "laut": -1.8, "lärm": -1.2, "unfreundlich": -2.2,
+		"enttäuschend": -2.5, "enttäuscht": -2.5, "teuer": -1.2, "klein": -0.6, "alt": -0.8,
+		"veraltet": -1.5, "kaputt": -2, "geruch": -1, "problem": -1.5, "langsam": -1.5,
+		"mittelmäßig": -1,
+	},
+	Negators: set("nicht", "kein", "keine", "keinen", "nie", "niemals", "nichts", "ohne"),
+	Boosters: map[string]float64{
+		"sehr": 1.3, "wirklich": 1.3, "zu": 1.2, "extrem": 1.5, "total": 1.3, "ziemlich": 1.1,
+		"etwas": 0.8,
+	},
+	Contrasts: set("aber", "jedoch", "allerdings", "obwohl", "außer"),
+}
+
+var vietnamese = &Lexicon{
+	Words: map[string]float64{
+		"tuyệt vời": 3.2, "xuất sắc": 3.2, "hoàn hảo": 3, "rất tốt": 2.5, "tốt": 1.8,
+		"đẹp": 2, "sạch sẽ": 1.8, "sạch": 1.6, "thoải mái": 1.8, "thân thiện": 2,
+		"nhiệt tình": 2, "chu đáo": 2, "yên tĩnh": 1.2, "rộng rãi": 1.5, "ngon": 2.5,
+		"hài lòng": 2, "thích": 2, "giới thiệu": 1, "tiện lợi": 1.5, "ổn": 0.9,
+
+		"tệ": -2.8, "tồi": -2.5, "kém": -2.2, "bẩn": -2.5, "dơ": -2.5, "ồn ào": -1.8,
+		"ồn": -1.5, "bất lịch sự": -2.8, "thất vọng": -2.5, "đắt": -1.2, "nhỏ": -0.6,
+		"cũ": -0.8, "hỏng": -2, "hôi": -2.2, "chậm": -1.5, "không hài lòng": -2.2,
+	},
+	Negators: set("không", "chẳng", "chưa", "chả"),
+	Boosters: map[string]float64{
+		"rất": 1.3, "quá": 1.2, "cực": 1.5, "khá": 1.1, "hơi": 0.8,
+	},
+	Contrasts: set("nhưng", "tuy", "mặc"),
+}
+
+var indonesian = &Lexicon{
+	Words: map[string]float64{
+		"luar biasa": 3.2, "sempurna": 3, "mantap": 2.5, "bagus": 2, "baik": 1.8,
+		"bersih": 1.6, "nyaman": 1.8, "ramah": 2, "sopan": 1.5, "tenang": 1.2,
+		"luas": 1.5, "enak": 2.5, "lezat": 2.5, "puas": 2, "rekomendasi": 2,
+		"strategis": 1.5, "cukup": 0.5, "lumayan": 0.8,
+
+		"buruk": -2.5, "jelek": -2.5, "parah": -3, "kotor": -2.5, "berisik": -1.8,
+		"bising": -1.8, "kasar": -2.5, "kecewa": -2.5, "mengecewakan": -2.5, "mahal": -1.2,
+		"kecil": -0.6, "tua": -0.8, "rusak": -2, "bau": -2.2, "lambat": -1.5,
+		"lama": -0.8,
+	},
+	Negators: set("tidak", "tak", "bukan", "belum", "tanpa", "kurang"),
+	Boosters: map[string]float64{
+		"sangat": 1.3, "sekali": 1.3, "banget": 1.3, "terlalu": 1.2, "agak": 0.8,
+	},
+	Contrasts: set("tetapi", "tapi", "namun", "meskipun"),
+}
@@ -0,0 +1,257 @@
+// Package sentiment scores review text offline against per-language
+// lexicons. Scoring follows the usual lexicon approach: word valences are
+// summed, flipped after a negator, scaled by boosters such as "very", and
+// shifted toward the clause after a contrast such as "but". Lexicons are
+// registered per ISO 639-1 code, so a language is added by calling Register.
+package sentiment
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Labels.
+const (
+	Positive = "positive"
+	Neutral  = "neutral"
+	Negative = "negative"
+)
+
+const (
+	// NeutralBand is how far from zero a score must be to count as positive
+	// or negative.
+	NeutralBand = 0.05
+	// TitleWeight is how much more a word in the title counts than one in the
+	// text. Titles tend to be the reviewer's verdict ("Would not recommend").
+	TitleWeight = 2
+
+	negationWindow = 3    // words a negator reaches
+	negationFactor = -0.9 // "not great" is a little less bad than "awful"
+	beforeContrast = 0.5  // weight of the clause before "but"
+	afterContrast  = 1.5  // weight of the clause after it
+	normalizeAlpha = 15   // how fast the summed valence approaches ±1
+	maxPhraseWords = 3
+)
+
+// Lexicon holds the word lists for one language.
+type Lexicon struct {
+	// Words maps a lowercased word or phrase of up to three words to its
+	// valence, roughly -4 (worst) to 4 (best).
+	Words map[string]float64
+	// Negators flip the valence of the next few words.
+	Negators map[string]bool
+	// Boosters scale the valence of the next word, e.g. "very" 1.3 or
+	// "slightly" 0.7.
+	Boosters map[string]float64
+	// Contrasts shift a sentence's weight to the clause that follows them.
+	Contrasts map[string]bool
+}
+
+// Result is the sentiment of a review.
+type Result struct {
+	// Score is between -1 and 1.
+	Score float64
+	Label string
+	// Hits is the number of lexicon words and phrases found.
+	Hits int
+}
+
+var (
+	mu       sync.RWMutex
+	lexicons = map[string]*Lexicon{}
+)
+
+// Register makes lex the lexicon for lang, replacing any registered before.
+func Register(lang string, lex *Lexicon) {
+	mu.Lock()
+	defer mu.Unlock()
+	lexicons[lang] = lex
+}
+
+// Languages lists the codes a lexicon is registered for.
+func Languages() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	langs := make([]string, 0, len(lexicons))
+	for lang := range lexicons {
+		langs = append(langs, lang)
+	}
+	return langs
+}
+
+// Supports reports whether a lexicon is registered for lang.
+func Supports(lang string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return lexicons[lang] != nil
+}
+
+// Score scores a review's title and text with the lexicon for lang. Text in
+// an undetermined language ("") is scored with the English lexicon. ok is
+// false when there is no lexicon for lang.
+func Score(title, text, lang string) (Result, bool) {
+	lex := lexicon(lang)
+	if lex == nil {
+		return Result{}, false
+	}
+
+	titleSum, titleHits := lex.valence(title)
+	textSum, textHits := lex.valence(text)
+	sum := TitleWeight*titleSum + textSum
+
+	score := sum / math.Sqrt(sum*sum+normalizeAlpha)
+	return Result{Score: score, Label: Label(score), Hits: titleHits + textHits}, true
+}
+
+// ScoreClause scores a single clause, as split by Clauses, with the lexicon
+// for lang.
+func ScoreClause(words []string, lang string) (Result, bool) {
+	lex := lexicon(lang)
+	if lex == nil {
+		return Result{}, false
+	}
+	sum, hits := lex.sentence(words)
+	score := sum / math.Sqrt(sum*sum+normalizeAlpha)
+	return Result{Score: score, Label: Label(score), Hits: hits}, true
+}
+
+// Clauses splits a sentence's words at the contrast words of lang's lexicon,
+// so "friendly but noisy" yields "friendly" and "noisy".
+func Clauses(words []string, lang string) [][]string {
+	lex := lexicon(lang)
+	if lex == nil {
+		return [][]string{words}
+	}
+	var out [][]string
+	start := 0
+	for i, w := range words {
+		if lex.Contrasts[w] {
+			if i > start {
+				out = append(out, words[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(words) {
+		out = append(out, words[start:])
+	}
+	return out
+}
+
+// lexicon returns the lexicon for lang, English for "", or nil.
+func lexicon(lang string) *Lexicon {
+	if lang == "" {
+		lang = "en"
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	return lexicons[lang]
+}
+
+// Label maps a score to positive, neutral or negative.
+func Label(score float64) string {
+	switch {
+	case score > NeutralBand:
+		return Positive
+	case score < -NeutralBand:
+		return Negative
+	default:
+		return Neutral
+	}
+}
+
+// valence returns the summed valence of every sentence in s and the number
+// of lexicon entries found.
+func (lex *Lexicon) valence(s string) (float64, int) {
+	total, hits := 0.0, 0
+	for _, sentence := range Sentences(s) {
+		v, n := lex.sentence(Words(sentence))
+		total += v
+		hits += n
+	}
+	return total, hits
+}
+
+func (lex *Lexicon) sentence(words []string) (float64, int) {
+	var before, after float64
+	contrasted := false
+	negated := 0
+	boost := 1.0
+	hits := 0
+
+	for i := 0; i < len(words); {
+		if lex.Contrasts[words[i]] {
+			contrasted = true
+			negated, boost = 0, 1
+			i++
+			continue
+		}
+
+		// Longest phrase first, so "not bad" or "could be better" win over
+		// their words.
+		if v, n := lex.lookup(words[i:]); n > 0 {
+			v *= boost
+			if negated > 0 {
+				v *= negationFactor
+			}
+			if contrasted {
+				after += v
+			} else {
+				before += v
+			}
+			hits++
+			boost = 1
+			negated -= n
+			i += n
+			continue
+		}
+
+		w := words[i]
+		switch {
+		case lex.Negators[w] || strings.HasSuffix(w, "n't"):
+			negated = negationWindow
+		case lex.Boosters[w] != 0:
+			boost *= lex.Boosters[w]
+		default:
+			boost = 1
+			if negated > 0 {
+				negated--
+			}
+		}
+		i++
+	}
+
+	if contrasted {
+		return beforeContrast*before + afterContrast*after, hits
+	}
+	return before, hits
+}
+
+// lookup matches the longest lexicon entry at the start of words and returns
+// its valence and length in words.
+func (lex *Lexicon) lookup(words []string) (float64, int) {
+	for n := min(maxPhraseWords, len(words)); n > 0; n-- {
+		if v, ok := lex.Words[strings.Join(words[:n], " ")]; ok {
+			return v, n
+		}
+	}
+	return 0, 0
+}
+
+// Sentences splits text at sentence punctuation and line breaks.
+func Sentences(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return r == '.' || r == '!' || r == '?' || r == ';' || r == '\n' || r == '。'
+	})
+}
+
+// Words lowercases s and splits it into words, keeping apostrophes so
+// "didn't" stays one word.
+func Words(s string) []string {
+	s = strings.ReplaceAll(strings.ToLower(s), "’", "'")
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+}
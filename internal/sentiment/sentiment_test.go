@@ -0,0 +1,87 @@
+package sentiment
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestScoreNegation(t *testing.T) {
+	tests := []struct {
+		lang string
+		text string
+		want string
+	}{
+		{"en", "The room was clean", Positive},
+		{"en", "The room was not clean", Negative},
+		{"en", "The room wasn't clean", Negative},
+		{"en", "The room wasn’t clean", Negative},
+		{"en", "The room was not very clean", Negative},
+		{"en", "Staff were never rude", Positive},
+		{"en", "Nothing was dirty", Positive},
+		{"en", "not at all dirty", Positive},
+		{"en", "not the best", Negative},
+		{"en", "Would not recommend", Negative},
+
+		// Phrases override the negated word.
+		{"en", "not bad", Positive},
+		{"en", "not bad at all", Positive},
+		{"en", "We had no problems", Positive},
+
+		// A negator reaches three words, and not past a contrast.
+		{"en", "not that the staff were friendly", Positive},
+		{"en", "not great but clean", Positive},
+
+		{"fr", "la chambre n'était pas propre", Negative},
+		{"es", "la habitación no estaba limpia", Negative},
+		{"de", "das Zimmer war nicht sauber", Negative},
+		{"vi", "phòng không sạch", Negative},
+		{"id", "kamar tidak bersih", Negative},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang+"/"+tt.text, func(t *testing.T) {
+			res, ok := Score("", tt.text, tt.lang)
+			if !ok {
+				t.Fatalf("no lexicon for %s", tt.lang)
+			}
+			if res.Label != tt.want {
+				t.Errorf("Score(%q) = %s (%+.2f), want %s", tt.text, res.Label, res.Score, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoreNegationFactor(t *testing.T) {
+	// "not great" is a little less bad than a word as negative as "great" is
+	// positive.
+	plain, _ := Score("", "great", "en")
+	negated, _ := Score("", "not great", "en")
+	if negated.Score >= 0 || -negated.Score >= plain.Score {
+		t.Errorf("not great = %+.2f, great = %+.2f", negated.Score, plain.Score)
+	}
+}
+
+func TestScoreTitleAndContrast(t *testing.T) {
+	// The title counts double, so a negative verdict outweighs a mildly
+	// positive text.
+	if res, _ := Score("Would not recommend", "The room was clean", "en"); res.Label != Negative {
+		t.Errorf("title verdict = %s (%+.2f), want negative", res.Label, res.Score)
+	}
+	// The clause after "but" outweighs the one before it.
+	if res, _ := Score("", "The staff were friendly but the room was dirty", "en"); res.Label != Negative {
+		t.Errorf("contrast = %s (%+.2f), want negative", res.Label, res.Score)
+	}
+	if _, ok := Score("", "ok", "xx"); ok {
+		t.Error("scored a language without a lexicon")
+	}
+	if res, _ := Score("", "We stayed two nights", ""); res.Label != Neutral || res.Hits != 0 {
+		t.Errorf("no lexicon words = %s with %d hits, want neutral", res.Label, res.Hits)
+	}
+}
+
+func TestClauses(t *testing.T) {
+	got := Clauses(Words("Friendly, but noisy; however cheap"), "en")
+	want := [][]string{{"friendly"}, {"noisy"}, {"cheap"}}
+	if !slices.EqualFunc(got, want, slices.Equal[[]string]) {
+		t.Errorf("Clauses = %v, want %v", got, want)
+	}
+}
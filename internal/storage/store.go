@@ -0,0 +1,62 @@
+// Package storage abstracts where daily review files are read from, so the
+// ingestion scheduler and backfill can run against AWS S3, an S3-compatible
+// endpoint such as MinIO, or a local directory.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// ErrNotFound is returned by Stat and Open when the key does not exist.
+var ErrNotFound = errors.New("object not found")
+
+// ObjectInfo describes a stored object. ETag changes whenever the content
+// does, so callers can tell a re-uploaded file from the one they processed.
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ETag         string
+	LastModified time.Time
+}
+
+// ObjectStore is a read-only view of a bucket or directory.
+type ObjectStore interface {
+	// Name identifies the bucket or directory; it prefixes processed markers.
+	Name() string
+	// List returns every object whose key starts with prefix.
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// Stat returns the object's metadata, or ErrNotFound.
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	// Open reads the object starting at byte offset.
+	Open(ctx context.Context, key string, offset int64) (io.ReadCloser, error)
+}
+
+// NewFromEnv builds the store selected by OBJECT_STORE ("s3" or "local").
+func NewFromEnv(ctx context.Context) (ObjectStore, error) {
+	switch kind := getEnv("OBJECT_STORE", "s3"); kind {
+	case "s3":
+		return NewS3Store(ctx, S3Config{
+			Bucket:       getEnv("S3_BUCKET", ""),
+			Region:       getEnv("AWS_REGION", "ap-south-1"),
+			Endpoint:     getEnv("S3_ENDPOINT", ""),
+			UsePathStyle: getEnv("S3_FORCE_PATH_STYLE", "false") == "true",
+			Anonymous:    getEnv("S3_ANONYMOUS", "false") == "true",
+		})
+	case "local":
+		return NewLocalStore(getEnv("LOCAL_STORE_DIR", "testdata"))
+	default:
+		return nil, fmt.Errorf("unknown OBJECT_STORE %q (want s3 or local)", kind)
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
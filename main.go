@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
 	"log"
 	"time"
 
 	_ "review-system/docs"
 	"review-system/internal/ingestion"
+	"review-system/internal/storage"
 	"review-system/models"
 	"review-system/routes"
 
@@ -20,6 +22,11 @@ import (
 // @host localhost:8080
 // @BasePath /
 
+// @securityDefinitions.apikey AdminToken
+// @in header
+// @name Authorization
+// @description "Bearer <token>", with a token from ADMIN_TOKENS
+
 func main() {
 	// Load .env file for local development
 	if err := godotenv.Load(); err != nil {
@@ -28,6 +35,18 @@ func main() {
 
 	// Initialize DB using environment
 	models.InitDB()
+
+	// Reviews and replies stored before redaction existed still carry personal data
+	if n, err := ingestion.RedactExistingReviews(models.GetDB()); err != nil {
+		log.Fatalf("❌ Failed to redact existing reviews: %v", err)
+	} else if n > 0 {
+		log.Printf("🕶️  Redacted personal data from %d existing reviews", n)
+	}
+	if n, err := ingestion.RedactExistingResponses(models.GetDB()); err != nil {
+		log.Fatalf("❌ Failed to redact existing responses: %v", err)
+	} else if n > 0 {
+		log.Printf("🕶️  Redacted personal data from %d existing responses", n)
+	}
 	// Check if reviews already exist
 	// var count int64
 	// models.GetDB().Model(&models.Review{}).Count(&count)
@@ -37,9 +56,26 @@ func main() {
 	// handlers.IngestJLFileAsync("testdata/2025-04-19.jl") // ✅ fire and forget
 	// }
 
+	// Create or validate the raw, retry, DLQ and events topics
+	if err := ingestion.EnsureTopics(); err != nil {
+		if ingestion.TopicConfigStrict {
+			log.Fatalf("❌ Kafka topic provisioning failed: %v", err)
+		}
+		log.Printf("⚠️ Kafka topic provisioning failed: %v", err)
+	}
+
 	// Start Kafka consumer to ingest reviews
 	ingestion.StartKafkaConsumer()
 
+	// Publish review change events written by the ingestion sink
+	ingestion.StartOutboxRelay()
+
+	// Daily files are read from S3, an S3-compatible endpoint or a local directory
+	store, err := storage.NewFromEnv(context.Background())
+	if err != nil {
+		log.Fatalf("❌ Object store setup failed: %v", err)
+	}
+
 	// Periodic daily ingestion check
 	ticker := time.NewTicker(1 * time.Hour)
 	defer ticker.Stop()
@@ -53,7 +89,7 @@ func main() {
 
 			if today != lastIngested {
 				log.Printf("📆 Running ingestion for %s\n", today)
-				ingestion.StartS3StreamIngestion()
+				ingestion.StartS3StreamIngestion(store)
 				lastIngested = today
 			}
 
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"review-system/internal/aspects"
+
+	"gorm.io/gorm"
+)
+
+// ReviewAspectMention is an aspect such as staff or noise named in one
+// sentence of a review, with the polarity of the clause naming it. Unlike
+// ReviewSubRating it comes from the review text, not from the provider.
+type ReviewAspectMention struct {
+	ID       uint   `gorm:"primaryKey"`
+	ReviewID uint   `gorm:"index"`
+	Aspect   string `gorm:"size:32;index"`
+	Polarity string `gorm:"size:8"` // positive, neutral or negative
+	Score    float32
+	Sentence string
+}
+
+// SaveAspectMentions replaces a stored review's aspect mentions with the ones
+// extracted from its title and text.
+func SaveAspectMentions(tx *gorm.DB, review Review) error {
+	if err := tx.Where("review_id = ?", review.ID).Delete(&ReviewAspectMention{}).Error; err != nil {
+		return err
+	}
+	var mentions []ReviewAspectMention
+	for _, m := range aspects.Extract(review.ReviewTitle, review.ReviewText, review.Language) {
+		mentions = append(mentions, ReviewAspectMention{
+			ReviewID: review.ID,
+			Aspect:   m.Aspect,
+			Polarity: m.Polarity,
+			Score:    float32(m.Score),
+			Sentence: m.Sentence,
+		})
+	}
+	if len(mentions) == 0 {
+		return nil
+	}
+	return tx.Create(&mentions).Error
+}
+
+// extractExistingAspectMentions extracts mentions for reviews stored before
+// they were extracted during ingestion.
+func extractExistingAspectMentions(db *gorm.DB) error {
+	var batch []Review
+	return db.Select("id", "review_title", "review_text", "language").
+		FindInBatches(&batch, 1000, func(tx *gorm.DB, _ int) error {
+			for _, r := range batch {
+				if err := SaveAspectMentions(tx, r); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
@@ -25,7 +25,25 @@ func InitDB() {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
-	DB.AutoMigrate(&Platform{}, &Hotel{}, &Reviewer{}, &Review{}, &HotelRatingsSummary{})
+	if !DB.Migrator().HasTable(&HotelPlatformListing{}) {
+		// hotels.external_id used to be globally unique; it is only unique per platform.
+		DB.Exec("DROP INDEX IF EXISTS idx_hotels_external_id")
+	}
+
+	if err := DB.AutoMigrate(&Platform{}, &Hotel{}, &HotelPlatformListing{}, &HotelMatchCandidate{},
+		&Country{}, &TravelerType{}, &RoomType{}, &ReviewerProfile{},
+		&Reviewer{}, &Review{}, &ReviewSubRating{}, &ReviewAspectMention{}, &DuplicateCluster{}, &DuplicateBand{},
+		&ModerationAction{}, &ManagementResponse{}, &HotelRatingsSummary{}, &HotelAspectSummary{}, &QualityViolationCount{}, &QualityOutcomeCount{}, &OutboxEvent{},
+		&SchemaMigration{}); err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+
+	if err := createReviewSortIndexes(DB); err != nil {
+		log.Fatal("Failed to create review sort indexes:", err)
+	}
+	if err := runDataMigrations(DB); err != nil {
+		log.Fatal("Failed to migrate existing data:", err)
+	}
 }
 
 func GetDB() *gorm.DB {
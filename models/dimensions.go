@@ -0,0 +1,196 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Country is the reviewer's country. Countries providers spell in a way we
+// can map to ISO 3166-1 carry its alpha-2 code and common name; anything
+// else is kept under the provider's spelling with no code.
+type Country struct {
+	ID      uint    `gorm:"primaryKey"`
+	ISOCode *string `gorm:"size:2;uniqueIndex"`
+	Name    string  `gorm:"uniqueIndex"`
+}
+
+// TravelerType is the group the reviewer travelled as, e.g. "Family" or
+// "Solo traveler".
+type TravelerType struct {
+	ID   uint   `gorm:"primaryKey"`
+	Name string `gorm:"uniqueIndex"`
+}
+
+// RoomType is a room type as a platform names it for one listing. Room
+// names are specific to the platform, so they hang off the listing and
+// follow it when listings are merged or split between hotels.
+type RoomType struct {
+	ID        uint   `gorm:"primaryKey"`
+	ListingID uint   `gorm:"uniqueIndex:idx_room_type"`
+	Name      string `gorm:"uniqueIndex:idx_room_type"`
+}
+
+// ReviewerProfile is a reviewer's identity on one platform. It only exists
+// when the provider sends a reviewer ID; anonymous reviews have none.
+type ReviewerProfile struct {
+	ID                 uint   `gorm:"primaryKey"`
+	PlatformID         uint   `gorm:"uniqueIndex:idx_reviewer_profile"`
+	ExternalReviewerID string `gorm:"uniqueIndex:idx_reviewer_profile"`
+	DisplayName        string
+	ReviewCount        int // as reported by the platform
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// ReviewerInfo is the reviewer part of a provider record.
+type ReviewerInfo struct {
+	CountryName  string
+	TravelerType string
+	RoomTypeName string
+	ReviewerID   string
+	DisplayName  string
+	ReviewCount  int
+	StayDate     *time.Time
+	LengthOfStay int
+}
+
+// ReviewDimensions holds the dimension IDs a review points at. Each is nil
+// when the provider did not send that field.
+type ReviewDimensions struct {
+	CountryID         *uint
+	TravelerTypeID    *uint
+	RoomTypeID        *uint
+	ReviewerProfileID *uint
+}
+
+// ResolveDimensions returns the dimension rows for a review's reviewer info,
+// creating any that don't exist yet. Creation is safe against concurrent
+// workers: a failed insert falls back to reading the row the winner wrote.
+func ResolveDimensions(db *gorm.DB, platformID, listingID uint, info ReviewerInfo) (ReviewDimensions, error) {
+	var dims ReviewDimensions
+
+	if info.CountryName != "" {
+		country := countryFor(info.CountryName)
+		if err := firstOrCreate(db, &country, "name = ?", country.Name); err != nil {
+			return dims, fmt.Errorf("error resolving country %q: %w", info.CountryName, err)
+		}
+		dims.CountryID = &country.ID
+	}
+
+	if info.TravelerType != "" {
+		travelerType := TravelerType{Name: info.TravelerType}
+		if err := firstOrCreate(db, &travelerType, "name = ?", travelerType.Name); err != nil {
+			return dims, fmt.Errorf("error resolving traveler type %q: %w", info.TravelerType, err)
+		}
+		dims.TravelerTypeID = &travelerType.ID
+	}
+
+	if info.RoomTypeName != "" {
+		roomType := RoomType{ListingID: listingID, Name: info.RoomTypeName}
+		if err := firstOrCreate(db, &roomType, "listing_id = ? AND name = ?", listingID, roomType.Name); err != nil {
+			return dims, fmt.Errorf("error resolving room type %q: %w", info.RoomTypeName, err)
+		}
+		dims.RoomTypeID = &roomType.ID
+	}
+
+	if info.ReviewerID != "" {
+		profile := ReviewerProfile{
+			PlatformID:         platformID,
+			ExternalReviewerID: info.ReviewerID,
+			DisplayName:        info.DisplayName,
+			ReviewCount:        info.ReviewCount,
+		}
+		if err := firstOrCreate(db, &profile, "platform_id = ? AND external_reviewer_id = ?", platformID, info.ReviewerID); err != nil {
+			return dims, fmt.Errorf("error resolving reviewer %q: %w", info.ReviewerID, err)
+		}
+		// Keep the latest name and count the platform reports.
+		if profile.DisplayName != info.DisplayName || profile.ReviewCount != info.ReviewCount {
+			db.Model(&profile).Updates(map[string]interface{}{
+				"display_name": info.DisplayName,
+				"review_count": info.ReviewCount,
+			})
+		}
+		dims.ReviewerProfileID = &profile.ID
+	}
+
+	return dims, nil
+}
+
+// firstOrCreate loads the row matching query into dest, or inserts dest if
+// there is none. A failed insert is usually a duplicate from another worker,
+// so the row is read again before giving up.
+func firstOrCreate(db *gorm.DB, dest interface{}, query string, args ...interface{}) error {
+	err := db.Where(query, args...).First(dest).Error
+	if err != gorm.ErrRecordNotFound {
+		return err
+	}
+	if err := db.Create(dest).Error; err != nil {
+		return db.Where(query, args...).First(dest).Error
+	}
+	return nil
+}
+
+// countryFor maps a provider's country spelling to a Country, using the ISO
+// name when the spelling is recognized.
+func countryFor(name string) Country {
+	name = strings.TrimSpace(name)
+	if code := CountryCode(name); code != "" {
+		return Country{ISOCode: &code, Name: CountryName(code)}
+	}
+	return Country{Name: name}
+}
+
+// migrateReviewerDimensions moves reviews off the legacy reviewers table,
+// which stored each (country, traveler group, room type) combination as a
+// "reviewer", onto the separate dimensions.
+func migrateReviewerDimensions(db *gorm.DB) error {
+	var names []string
+	if err := db.Model(&Reviewer{}).Distinct().Where("country_name <> ''").
+		Pluck("country_name", &names).Error; err != nil {
+		return err
+	}
+	for _, name := range names {
+		country := countryFor(name)
+		if err := firstOrCreate(db, &country, "name = ?", country.Name); err != nil {
+			return err
+		}
+		if err := db.Exec(`
+            UPDATE reviews SET country_id = ?
+            WHERE country_id IS NULL
+              AND reviewer_id IN (SELECT id FROM reviewers WHERE country_name = ?)
+        `, country.ID, name).Error; err != nil {
+			return err
+		}
+	}
+
+	statements := []string{
+		`INSERT INTO traveler_types (name)
+        SELECT DISTINCT review_group_name FROM reviewers WHERE review_group_name <> ''
+        ON CONFLICT DO NOTHING`,
+		`INSERT INTO room_types (listing_id, name)
+        SELECT DISTINCT r.listing_id, rv.room_type_name
+        FROM reviews r
+        JOIN reviewers rv ON rv.id = r.reviewer_id
+        WHERE rv.room_type_name <> '' AND r.listing_id IS NOT NULL AND r.listing_id <> 0
+        ON CONFLICT DO NOTHING`,
+		`UPDATE reviews r
+        SET traveler_type_id = t.id
+        FROM reviewers rv
+        JOIN traveler_types t ON t.name = rv.review_group_name
+        WHERE rv.id = r.reviewer_id AND r.traveler_type_id IS NULL`,
+		`UPDATE reviews r
+        SET room_type_id = rt.id
+        FROM reviewers rv, room_types rt
+        WHERE rv.id = r.reviewer_id AND rt.listing_id = r.listing_id
+          AND rt.name = rv.room_type_name AND r.room_type_id IS NULL`,
+	}
+	for _, stmt := range statements {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+package models
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"review-system/internal/dedupe"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DuplicateCluster groups reviews of a hotel whose texts are near-identical:
+// the same guest posting on several platforms, or a provider re-issuing a
+// review under a new ID. The primary review, the first visible one stored,
+// is the one counted when aggregates count a cluster once.
+type DuplicateCluster struct {
+	ID              uint `gorm:"primaryKey"`
+	PrimaryReviewID uint
+	CreatedAt       time.Time
+}
+
+func (DuplicateCluster) TableName() string {
+	return "review_duplicate_clusters"
+}
+
+// DuplicateBand is one locality-sensitive hash band of a review's MinHash
+// signature. Only reviews sharing a band with a new review are compared
+// with it, rather than every review of the hotel in the window.
+type DuplicateBand struct {
+	ReviewID uint  `gorm:"primaryKey;autoIncrement:false"`
+	BandHash int64 `gorm:"primaryKey;autoIncrement:false;index"`
+}
+
+func (DuplicateBand) TableName() string {
+	return "review_duplicate_bands"
+}
+
+// CountClusterOnce is a condition on reviews aliased r that leaves out every
+// member of a duplicate cluster but its primary review.
+const CountClusterOnce = `NOT EXISTS (SELECT 1 FROM review_duplicate_clusters dc
+        WHERE dc.id = r.duplicate_cluster_id AND dc.primary_review_id <> r.id)`
+
+var (
+	duplicateConfigOnce sync.Once
+	duplicateThreshold  float64
+	duplicateWindow     time.Duration
+)
+
+// duplicateConfig reads DUPLICATE_SIMILARITY, the MinHash similarity from
+// which two reviews are duplicates (default 0.7), and DUPLICATE_WINDOW_DAYS,
+// how far apart their dates may be (default 30).
+func duplicateConfig() (float64, time.Duration) {
+	duplicateConfigOnce.Do(func() {
+		duplicateThreshold, duplicateWindow = 0.7, 30*24*time.Hour
+		if v := os.Getenv("DUPLICATE_SIMILARITY"); v != "" {
+			if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 && f <= 1 {
+				duplicateThreshold = f
+			} else {
+				log.Printf("⚠️  Ignoring DUPLICATE_SIMILARITY %q: want a number in (0, 1]", v)
+			}
+		}
+		if v := os.Getenv("DUPLICATE_WINDOW_DAYS"); v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+				duplicateWindow = time.Duration(n) * 24 * time.Hour
+			} else {
+				log.Printf("⚠️  Ignoring DUPLICATE_WINDOW_DAYS %q: want a whole number of days", v)
+			}
+		}
+	})
+	return duplicateThreshold, duplicateWindow
+}
+
+// SignReview sets the review's MinHash signature from its text.
+func (r *Review) SignReview() {
+	r.MinHash = dedupe.Sign(r.ReviewText).Bytes()
+}
+
+// LinkDuplicates stores a review's signature bands, compares the review with
+// the hotel's other reviews dated within the duplicate window that share a
+// band, and puts it in a cluster with those it nearly duplicates. Clusters it
+// bridges are merged. The review's cluster and similarity are set on it as
+// well as stored.
+func LinkDuplicates(tx *gorm.DB, review *Review) error {
+	sig := dedupe.FromBytes(review.MinHash)
+	bands := sig.BandHashes()
+	if err := saveDuplicateBands(tx, review.ID, bands); err != nil {
+		return err
+	}
+	if bands == nil || review.ReviewDate == nil {
+		return nil
+	}
+	threshold, window := duplicateConfig()
+
+	var candidates []Review
+	if err := tx.Select("id", "min_hash", "duplicate_cluster_id").
+		Where("hotel_id = ? AND id <> ? AND min_hash IS NOT NULL AND review_date BETWEEN ? AND ?",
+			review.HotelID, review.ID, review.ReviewDate.Add(-window), review.ReviewDate.Add(window)).
+		Where("id IN (SELECT review_id FROM review_duplicate_bands WHERE band_hash IN ?)", bands).
+		Find(&candidates).Error; err != nil {
+		return err
+	}
+
+	var matched []Review
+	similarity := make(map[uint]float64)
+	best := 0.0
+	var clusterID uint
+	for _, c := range candidates {
+		sim := dedupe.Similarity(sig, dedupe.FromBytes(c.MinHash))
+		if sim < threshold {
+			continue
+		}
+		matched = append(matched, c)
+		similarity[c.ID] = sim
+		best = max(best, sim)
+		if c.DuplicateClusterID != nil && (clusterID == 0 || *c.DuplicateClusterID < clusterID) {
+			clusterID = *c.DuplicateClusterID
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+
+	if clusterID == 0 {
+		cluster := DuplicateCluster{PrimaryReviewID: review.ID}
+		if err := tx.Create(&cluster).Error; err != nil {
+			return err
+		}
+		clusterID = cluster.ID
+	}
+
+	// Merge the other clusters the review bridges into the lowest one.
+	var merged []uint
+	for _, m := range matched {
+		if m.DuplicateClusterID != nil && *m.DuplicateClusterID != clusterID {
+			merged = append(merged, *m.DuplicateClusterID)
+		}
+	}
+	if len(merged) > 0 {
+		if err := tx.Model(&Review{}).Where("duplicate_cluster_id IN ?", merged).
+			Update("duplicate_cluster_id", clusterID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id IN ?", merged).Delete(&DuplicateCluster{}).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, m := range matched {
+		if m.DuplicateClusterID != nil {
+			continue
+		}
+		if err := tx.Model(&Review{}).Where("id = ?", m.ID).Updates(map[string]interface{}{
+			"duplicate_cluster_id": clusterID,
+			"duplicate_similarity": similarity[m.ID],
+		}).Error; err != nil {
+			return err
+		}
+	}
+	sim := float32(best)
+	review.DuplicateClusterID, review.DuplicateSimilarity = &clusterID, &sim
+	if err := tx.Model(&Review{}).Where("id = ?", review.ID).Updates(map[string]interface{}{
+		"duplicate_cluster_id": clusterID,
+		"duplicate_similarity": sim,
+	}).Error; err != nil {
+		return err
+	}
+	return ElectPrimary(tx, clusterID)
+}
+
+// saveDuplicateBands replaces the stored bands of a review.
+func saveDuplicateBands(tx *gorm.DB, reviewID uint, bands []int64) error {
+	if err := tx.Where("review_id = ?", reviewID).Delete(&DuplicateBand{}).Error; err != nil {
+		return err
+	}
+	if len(bands) == 0 {
+		return nil
+	}
+	rows := make([]DuplicateBand, len(bands))
+	for i, b := range bands {
+		rows[i] = DuplicateBand{ReviewID: reviewID, BandHash: b}
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+// UnlinkDuplicate takes a review out of its duplicate cluster, before it is
+// deleted or its text changes. A cluster left with one review is dissolved.
+// The review's signature bands are dropped with it.
+func UnlinkDuplicate(tx *gorm.DB, review Review) error {
+	if err := saveDuplicateBands(tx, review.ID, nil); err != nil {
+		return err
+	}
+	if review.DuplicateClusterID == nil {
+		return nil
+	}
+	clusterID := *review.DuplicateClusterID
+	if err := tx.Model(&Review{}).Where("id = ?", review.ID).Updates(map[string]interface{}{
+		"duplicate_cluster_id": nil,
+		"duplicate_similarity": nil,
+	}).Error; err != nil {
+		return err
+	}
+
+	var remaining int64
+	if err := tx.Model(&Review{}).Where("duplicate_cluster_id = ?", clusterID).Count(&remaining).Error; err != nil {
+		return err
+	}
+	if remaining > 1 {
+		return ElectPrimary(tx, clusterID)
+	}
+	if err := tx.Model(&Review{}).Where("duplicate_cluster_id = ?", clusterID).Updates(map[string]interface{}{
+		"duplicate_cluster_id": nil,
+		"duplicate_similarity": nil,
+	}).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&DuplicateCluster{}, clusterID).Error
+}
+
+// ElectPrimary makes the first stored visible member the cluster's primary
+// review, or the first stored member when none is visible.
+func ElectPrimary(tx *gorm.DB, clusterID uint) error {
+	return tx.Exec(`
+        UPDATE review_duplicate_clusters
+        SET primary_review_id = (
+            SELECT id FROM reviews WHERE duplicate_cluster_id = ?
+            ORDER BY moderation_status <> ?, id LIMIT 1)
+        WHERE id = ?
+    `, clusterID, ModerationVisible, clusterID).Error
+}
+
+// linkExistingDuplicates signs reviews stored before duplicates were
+// detected and links them, oldest first, as if they were ingested again.
+func linkExistingDuplicates(db *gorm.DB) error {
+	var batch []Review
+	return db.Select("id", "hotel_id", "review_text", "review_date").Order("id").
+		FindInBatches(&batch, 1000, func(tx *gorm.DB, _ int) error {
+			for i := range batch {
+				r := &batch[i]
+				r.SignReview()
+				if r.MinHash == nil {
+					continue
+				}
+				if err := tx.Model(&Review{}).Where("id = ?", r.ID).Update("min_hash", r.MinHash).Error; err != nil {
+					return err
+				}
+				if err := LinkDuplicates(tx, r); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
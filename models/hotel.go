@@ -1,7 +1,199 @@
 package models
 
+import (
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata" // hotel timezones must resolve in minimal containers
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// Hotel is a canonical property. The same property usually appears on
+// several platforms, each with its own ID; those live in
+// HotelPlatformListing.
 type Hotel struct {
+	ID uint `gorm:"primaryKey"`
+	// ExternalID is the provider hotelId of the first listing seen for this
+	// hotel. It is not unique across providers; resolve through listings.
+	ExternalID     int `gorm:"index"`
+	Name           string
+	NormalizedName string `gorm:"index"`
+	Address        string
+	Latitude       *float64
+	Longitude      *float64
+	// Timezone is the hotel's IANA timezone, e.g. "Asia/Ho_Chi_Minh". Review
+	// dates are bucketed into days in it; empty means unknown.
+	Timezone string
+}
+
+var hotelLocations sync.Map // timezone name -> *time.Location
+
+// Location returns the hotel's timezone, or nil if it is unset or invalid.
+func (h Hotel) Location() *time.Location {
+	if h.Timezone == "" {
+		return nil
+	}
+	if loc, ok := hotelLocations.Load(h.Timezone); ok {
+		return loc.(*time.Location)
+	}
+	loc, err := time.LoadLocation(h.Timezone)
+	if err != nil {
+		return nil
+	}
+	hotelLocations.Store(h.Timezone, loc)
+	return loc
+}
+
+// ShiftReviewTimezone moves the dates of the reviews matching where from
+// one hotel timezone to another, after a hotel's timezone changed or reviews
+// moved to a hotel in another timezone. Dates sent with an offset keep their
+// instant and get a new local date; dates sent without one are wall-clock
+// times at the hotel, so they keep their local date and their instant moves.
+// An empty timezone means UTC for the latter and the provider's offset for
+// the former.
+func ShiftReviewTimezone(db *gorm.DB, oldTZ, newTZ string, where string, args ...interface{}) error {
+	if oldTZ == "" {
+		oldTZ = "UTC"
+	}
+	floatingTZ := newTZ
+	if floatingTZ == "" {
+		floatingTZ = "UTC"
+	}
+	if err := db.Exec(`
+        UPDATE reviews
+        SET review_date = (review_date AT TIME ZONE ?) AT TIME ZONE ?
+        WHERE review_date IS NOT NULL AND review_date_offset IS NULL AND `+where,
+		append([]interface{}{oldTZ, floatingTZ}, args...)...).Error; err != nil {
+		return err
+	}
+
+	if newTZ != "" {
+		return db.Exec(`
+            UPDATE reviews
+            SET review_local_date = (review_date AT TIME ZONE ?)::date
+            WHERE review_date IS NOT NULL AND review_date_offset IS NOT NULL AND `+where,
+			append([]interface{}{newTZ}, args...)...).Error
+	}
+	return db.Exec(`
+        UPDATE reviews
+        SET review_local_date = ((review_date AT TIME ZONE 'UTC') + review_date_offset * INTERVAL '1 second')::date
+        WHERE review_date IS NOT NULL AND review_date_offset IS NOT NULL AND `+where,
+		args...).Error
+}
+
+// HotelPlatformListing maps a provider's hotel ID to our canonical hotel.
+// Provider IDs are only unique within a platform, so the pair is the key.
+type HotelPlatformListing struct {
 	ID         uint `gorm:"primaryKey"`
-	ExternalID int  `gorm:"uniqueIndex"`
+	PlatformID uint `gorm:"uniqueIndex:idx_listing_identity"`
+	ExternalID int  `gorm:"uniqueIndex:idx_listing_identity"`
+	HotelID    uint `gorm:"index"`
 	Name       string
+	Address    string
+	Latitude   *float64
+	Longitude  *float64
+	CreatedAt  time.Time
+}
+
+// Hotel match candidate statuses.
+const (
+	MatchProposed  = "proposed"
+	MatchConfirmed = "confirmed"
+	MatchRejected  = "rejected"
+)
+
+// HotelMatchCandidate proposes that a listing belongs to an existing
+// canonical hotel. Proposals are only applied once an admin confirms them.
+type HotelMatchCandidate struct {
+	ID        uint    `gorm:"primaryKey"`
+	ListingID uint    `gorm:"uniqueIndex:idx_match_pair"`
+	HotelID   uint    `gorm:"uniqueIndex:idx_match_pair"`
+	Score     float64 // 0–1
+	Reason    string  // comma-separated signals, e.g. "name,geo"
+	Status    string  `gorm:"default:proposed;index"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+var hotelNameStopwords = map[string]bool{
+	"the": true, "hotel": true, "hotels": true, "and": true, "&": true,
+}
+
+// NormalizeHotelName reduces a hotel name to lowercase words without
+// punctuation or filler words, so "The Oscar Saigon Hotel" and "Oscar Saigon"
+// compare equal.
+func NormalizeHotelName(name string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '&' {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, name)
+
+	var words []string
+	for _, w := range strings.Fields(cleaned) {
+		if !hotelNameStopwords[w] {
+			words = append(words, w)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// migrateHotelListings creates a listing for every (hotel, platform) pair
+// seen in reviews before listings existed, and for every hotel without
+// reviews, and links those reviews to it.
+// Hotels that were wrongly merged across providers end up with one listing
+// per platform and can be split by an admin.
+func migrateHotelListings(db *gorm.DB) error {
+	var hotels []Hotel
+	if err := db.Find(&hotels).Error; err != nil {
+		return err
+	}
+	for _, h := range hotels {
+		if err := db.Model(&h).Update("normalized_name", NormalizeHotelName(h.Name)).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := db.Exec(`
+        INSERT INTO hotel_platform_listings (platform_id, external_id, hotel_id, name, created_at)
+        SELECT DISTINCT r.platform_id, h.external_id, h.id, h.name, NOW()
+        FROM reviews r
+        JOIN hotels h ON h.id = r.hotel_id
+        ON CONFLICT DO NOTHING
+    `).Error; err != nil {
+		return err
+	}
+
+	// A hotel whose reviews all failed or were deleted has no platform on
+	// record. Hotel IDs used to be unique across platforms, so it is listed
+	// under the platform most hotels came from; an admin can split it if
+	// that is wrong.
+	if err := db.Exec(`
+        INSERT INTO hotel_platform_listings (platform_id, external_id, hotel_id, name, created_at)
+        SELECT p.id, h.external_id, h.id, h.name, NOW()
+        FROM hotels h
+        CROSS JOIN (
+            SELECT p.id
+            FROM platforms p
+            LEFT JOIN hotel_platform_listings l ON l.platform_id = p.id
+            GROUP BY p.id
+            ORDER BY COUNT(l.id) DESC, p.id
+            LIMIT 1
+        ) p
+        WHERE NOT EXISTS (SELECT 1 FROM hotel_platform_listings l WHERE l.hotel_id = h.id)
+        ON CONFLICT DO NOTHING
+    `).Error; err != nil {
+		return err
+	}
+
+	return db.Exec(`
+        UPDATE reviews r
+        SET listing_id = l.id
+        FROM hotel_platform_listings l
+        WHERE l.hotel_id = r.hotel_id AND l.platform_id = r.platform_id
+          AND (r.listing_id IS NULL OR r.listing_id = 0)
+    `).Error
 }
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SchemaMigration records a data migration that has been applied. A
+// migration and its record are committed together, so one that fails partway
+// is rolled back and runs again at the next start.
+type SchemaMigration struct {
+	Name      string `gorm:"primaryKey"`
+	AppliedAt time.Time
+}
+
+// migrationLockKey is the Postgres advisory lock held while a data migration
+// runs, so instances starting together apply each migration once.
+const migrationLockKey = 7_270_002
+
+// dataMigration fills existing rows after AutoMigrate added the columns or
+// tables they need.
+type dataMigration struct {
+	Name    string
+	Message string
+	Run     func(tx *gorm.DB) error
+}
+
+// dataMigrations run in order; later ones read what earlier ones fill.
+var dataMigrations = []dataMigration{
+	{"normalize_ratings", "📐 Normalizing ratings of existing reviews...", normalizeExistingRatings},
+	{"hotel_listings", "🏨 Creating platform listings for existing hotels...", migrateHotelListings},
+	{"reviewer_dimensions", "🧳 Moving reviews onto country, traveler type and room type dimensions...", migrateReviewerDimensions},
+	{"review_local_dates", "📅 Filling local review dates...", migrateReviewDates},
+	{"review_languages", "🌐 Detecting the language of existing reviews...", detectExistingLanguages},
+	{"review_sentiment", "🙂 Scoring the sentiment of existing reviews...", scoreExistingSentiment},
+	{"aspect_mentions", "🔎 Extracting aspect mentions from existing reviews...", extractExistingAspectMentions},
+	{"duplicate_clusters", "👯 Linking near-duplicate reviews...", linkExistingDuplicates},
+	{"review_suspicion", "🚩 Scoring existing reviews for spam and fraud...", scoreExistingSuspicion},
+	{"review_search", "🔍 Indexing reviews for full-text search...", migrateReviewSearch},
+}
+
+// runDataMigrations applies the data migrations not yet recorded, each in
+// its own transaction together with its record.
+func runDataMigrations(db *gorm.DB) error {
+	for _, m := range dataMigrations {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockKey).Error; err != nil {
+				return err
+			}
+			var applied int64
+			if err := tx.Model(&SchemaMigration{}).Where("name = ?", m.Name).Count(&applied).Error; err != nil {
+				return err
+			}
+			if applied > 0 {
+				return nil
+			}
+			log.Println(m.Message)
+			if err := m.Run(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migration %s failed: %w", m.Name, err)
+		}
+	}
+	return nil
+}
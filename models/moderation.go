@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Review moderation statuses. Only visible reviews are served publicly and
+// counted in the rating summaries.
+const (
+	ModerationVisible = "visible"
+	ModerationPending = "pending" // held for review before it is shown
+	ModerationHidden  = "hidden"  // withdrawn from view, can be restored
+	ModerationRemoved = "removed" // taken down, e.g. for a policy violation
+)
+
+// ModerationStatuses lists every moderation status.
+var ModerationStatuses = []string{ModerationVisible, ModerationPending, ModerationHidden, ModerationRemoved}
+
+// VisibleOnly is a condition on reviews aliased r that keeps the reviews
+// served publicly.
+const VisibleOnly = "r.moderation_status = '" + ModerationVisible + "'"
+
+// Visible reports whether the review is served publicly.
+func (r Review) Visible() bool {
+	return r.ModerationStatus == ModerationVisible
+}
+
+// ModerationAction is the audit trail of moderation: one row per review
+// whose status an admin changed.
+type ModerationAction struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ReviewID   uint      `gorm:"index" json:"review_id"`
+	FromStatus string    `gorm:"size:8" json:"from_status"`
+	ToStatus   string    `gorm:"size:8" json:"to_status"`
+	Reason     string    `json:"reason"`
+	Actor      string    `gorm:"index" json:"actor"`
+	CreatedAt  time.Time `gorm:"index" json:"created_at"`
+}
+
+func (ModerationAction) TableName() string {
+	return "review_moderation_actions"
+}
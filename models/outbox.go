@@ -0,0 +1,106 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReviewEventsTopic is the topic review events are published to,
+// KAFKA_EVENTS_TOPIC or reviews.events.
+var ReviewEventsTopic = func() string {
+	if v := os.Getenv("KAFKA_EVENTS_TOPIC"); v != "" {
+		return v
+	}
+	return "reviews.events"
+}()
+
+// OutboxEvent is a pending message written in the same transaction as the
+// change it describes. The relay publishes rows in ID order and stamps
+// PublishedAt once the broker has acknowledged them.
+type OutboxEvent struct {
+	ID           uint64 `gorm:"primaryKey"`
+	Topic        string
+	PartitionKey string
+	EventType    string
+	Payload      string `gorm:"type:jsonb"`
+	CreatedAt    time.Time
+	PublishedAt  *time.Time `gorm:"index"`
+	Attempts     int        `gorm:"default:0"`
+}
+
+// ReviewEvent is the canonical "review ingested" message published to the
+// events topic. It carries our internal IDs so downstream teams never have to
+// look them up in Postgres.
+type ReviewEvent struct {
+	Op               string     `json:"op"` // insert, update or delete
+	ReviewID         uint       `json:"review_id"`
+	HotelReviewID    int64      `json:"hotel_review_id"`
+	HotelID          uint       `json:"hotel_id"`
+	HotelExternalID  int        `json:"hotel_external_id"`
+	PlatformID       uint       `json:"platform_id"`
+	Platform         string     `json:"platform"`
+	Rating           float32    `json:"rating"`
+	NormalizedRating float32    `json:"normalized_rating"`
+	ReviewDate       *time.Time `json:"review_date"`
+	ReviewLocalDate  string     `json:"review_local_date,omitempty"` // YYYY-MM-DD at the hotel
+	OccurredAt       time.Time  `json:"occurred_at"`
+}
+
+// EnqueueReviewEvent writes a ReviewEvent to the outbox using the caller's
+// transaction, so the event exists if and only if the change was committed.
+func EnqueueReviewEvent(tx *gorm.DB, op string, review Review, listing HotelPlatformListing, platform Platform) error {
+	payload, err := json.Marshal(ReviewEvent{
+		Op:               op,
+		ReviewID:         review.ID,
+		HotelReviewID:    review.HotelReviewID,
+		HotelID:          review.HotelID,
+		HotelExternalID:  listing.ExternalID,
+		PlatformID:       review.PlatformID,
+		Platform:         platform.Name,
+		Rating:           review.Rating,
+		NormalizedRating: review.NormalizedRating,
+		ReviewDate:       review.ReviewDate,
+		ReviewLocalDate:  formatDate(review.ReviewLocalDate),
+		OccurredAt:       time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode review event: %w", err)
+	}
+
+	event := OutboxEvent{
+		Topic:        ReviewEventsTopic,
+		PartitionKey: strconv.FormatUint(uint64(review.HotelID), 10),
+		EventType:    "review." + op,
+		Payload:      string(payload),
+	}
+	if err := tx.Create(&event).Error; err != nil {
+		return fmt.Errorf("failed to write outbox event: %w", err)
+	}
+	return nil
+}
+
+// EnqueueStoredReviewEvent is EnqueueReviewEvent for a stored review whose
+// listing and platform are looked up.
+func EnqueueStoredReviewEvent(tx *gorm.DB, op string, review Review) error {
+	var listing HotelPlatformListing
+	if err := tx.First(&listing, review.ListingID).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+	var platform Platform
+	if err := tx.First(&platform, review.PlatformID).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+	return EnqueueReviewEvent(tx, op, review, listing, platform)
+}
+
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
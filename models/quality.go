@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// QualityViolationCount counts how often a data quality rule was broken, per
+// day of ingestion (UTC), platform and rule. Action is the action the rule
+// last had for the platform.
+type QualityViolationCount struct {
+	Day      time.Time `gorm:"type:date;primaryKey"`
+	Platform string    `gorm:"primaryKey"`
+	Rule     string    `gorm:"primaryKey"`
+	Action   string
+	Count    int64
+	LastSeen time.Time
+}
+
+// QualityOutcomeCount counts records per day of ingestion (UTC), platform
+// and the action taken on the whole record, the most severe action among its
+// violations. A record breaking several rules counts once here.
+type QualityOutcomeCount struct {
+	Day      time.Time `gorm:"type:date;primaryKey"`
+	Platform string    `gorm:"primaryKey"`
+	Action   string    `gorm:"primaryKey"`
+	Count    int64
+	LastSeen time.Time
+}
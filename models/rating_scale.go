@@ -0,0 +1,146 @@
+package models
+
+import (
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// NormalizedScaleMax is the top of the common scale every platform's rating
+// is mapped onto; the bottom is 0.
+const NormalizedScaleMax = 10
+
+// RatingScale is the range and granularity a platform publishes ratings on.
+type RatingScale struct {
+	Min, Max, Step float32
+}
+
+// defaultRatingScales covers the platforms we ingest today. Others fall back
+// to a 0–10 scale, which leaves their ratings unchanged when normalized.
+// PLATFORM_RATING_SCALES overrides or extends this list, e.g.
+// "Expedia=1:5:1,Hotels.com=1:5:0.5".
+var defaultRatingScales = map[string]RatingScale{
+	"agoda":       {Min: 1, Max: 10, Step: 0.1},
+	"booking.com": {Min: 1, Max: 10, Step: 0.1},
+	"traveloka":   {Min: 1, Max: 10, Step: 0.1},
+	"expedia":     {Min: 1, Max: 5, Step: 1},
+	"hotels.com":  {Min: 1, Max: 5, Step: 1},
+}
+
+var fallbackRatingScale = RatingScale{Min: 0, Max: 10, Step: 0.1}
+
+func init() {
+entries:
+	for _, entry := range strings.Split(os.Getenv("PLATFORM_RATING_SCALES"), ",") {
+		name, spec, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+		parts := strings.Split(spec, ":")
+		if len(parts) != 3 {
+			log.Printf("⚠️  Ignoring rating scale %q: want name=min:max:step", entry)
+			continue
+		}
+		var vals [3]float32
+		for i, p := range parts {
+			f, err := strconv.ParseFloat(p, 32)
+			if err != nil {
+				log.Printf("⚠️  Ignoring rating scale %q: %v", entry, err)
+				continue entries
+			}
+			vals[i] = float32(f)
+		}
+		if vals[1] <= vals[0] {
+			log.Printf("⚠️  Ignoring rating scale %q: max must be above min", entry)
+			continue
+		}
+		defaultRatingScales[strings.ToLower(name)] = RatingScale{Min: vals[0], Max: vals[1], Step: vals[2]}
+	}
+}
+
+// DefaultRatingScale returns the configured scale for a platform name.
+func DefaultRatingScale(platform string) RatingScale {
+	if s, ok := defaultRatingScales[strings.ToLower(strings.TrimSpace(platform))]; ok {
+		return s
+	}
+	return fallbackRatingScale
+}
+
+// WithDefaultScale fills in the platform's scale if it has none yet.
+func (p Platform) WithDefaultScale() Platform {
+	if p.ScaleMax <= p.ScaleMin {
+		s := DefaultRatingScale(p.Name)
+		p.ScaleMin, p.ScaleMax, p.ScaleStep = s.Min, s.Max, s.Step
+	}
+	return p
+}
+
+// Normalize maps a rating on the platform's scale onto 0–NormalizedScaleMax,
+// clamping values outside the scale and rounding to two decimals.
+func (p Platform) Normalize(rating float32) float32 {
+	s := p.WithDefaultScale()
+	n := float64(rating-s.ScaleMin) / float64(s.ScaleMax-s.ScaleMin) * NormalizedScaleMax
+	n = math.Max(0, math.Min(NormalizedScaleMax, n))
+	return float32(math.Round(n*100) / 100)
+}
+
+// normalizeExistingRatings gives every platform a scale and fills
+// normalized_rating for reviews stored before it existed, then rebuilds the
+// ratings summaries on the normalized values.
+func normalizeExistingRatings(db *gorm.DB) error {
+	var platforms []Platform
+	if err := db.Find(&platforms).Error; err != nil {
+		return err
+	}
+	for _, p := range platforms {
+		if p.ScaleMax > p.ScaleMin {
+			continue
+		}
+		p = p.WithDefaultScale()
+		if err := db.Model(&p).Select("scale_min", "scale_max", "scale_step").Updates(p).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := db.Exec(`
+        UPDATE reviews r
+        SET normalized_rating = ROUND((LEAST(GREATEST((r.rating - p.scale_min) / (p.scale_max - p.scale_min), 0), 1) * ?)::numeric, 2)
+        FROM platforms p
+        WHERE p.id = r.platform_id
+    `, NormalizedScaleMax).Error; err != nil {
+		return err
+	}
+	return RecomputeHotelSummaries(db)
+}
+
+// RecomputeHotelSummaries rebuilds the pre-aggregated ratings of the given
+// hotels, or of every hotel when none are given, from their visible reviews.
+// Per-aspect summaries are rebuilt with them.
+func RecomputeHotelSummaries(db *gorm.DB, hotelIDs ...uint) error {
+	filter := ""
+	args := []interface{}{}
+	if len(hotelIDs) > 0 {
+		filter = "WHERE h.id IN ?"
+		args = append(args, hotelIDs)
+	}
+	if err := db.Exec(`
+        INSERT INTO hotel_ratings_summaries (hotel_id, total_reviews, total_rating, average_rating, last_updated)
+        SELECT h.id, COUNT(r.id), COALESCE(SUM(r.normalized_rating), 0), COALESCE(AVG(r.normalized_rating), 0), NOW()
+        FROM hotels h
+        LEFT JOIN reviews r ON r.hotel_id = h.id AND `+VisibleOnly+`
+        `+filter+`
+        GROUP BY h.id
+        ON CONFLICT (hotel_id) DO UPDATE SET
+            total_reviews = EXCLUDED.total_reviews,
+            total_rating = EXCLUDED.total_rating,
+            average_rating = EXCLUDED.average_rating,
+            last_updated = EXCLUDED.last_updated
+    `, args...).Error; err != nil {
+		return err
+	}
+	return RecomputeAspectSummaries(db, hotelIDs...)
+}
@@ -2,10 +2,12 @@ package models
 
 import "time"
 
+// HotelRatingsSummary holds a hotel's pre-aggregated ratings. Totals and the
+// average are on the normalized 0–10 scale, so platforms can be mixed.
 type HotelRatingsSummary struct {
-	HotelID       uint    `gorm:"primaryKey"`
-	TotalReviews  int     `gorm:"default:0"`
-	TotalRating   float64 `gorm:"default:0"`
-	AverageRating float64
-	LastUpdated   time.Time
+	HotelID       uint      `gorm:"primaryKey"`
+	TotalReviews  int       `gorm:"default:0;index"`
+	TotalRating   float64   `gorm:"default:0"`
+	AverageRating float64   `gorm:"index"`
+	LastUpdated   time.Time `gorm:"index"`
 }
@@ -1,12 +1,28 @@
 package models
 
-import "time"
+import (
+	"log"
+	"time"
+
+	"review-system/internal/langdetect"
+	"review-system/internal/sentiment"
+
+	"gorm.io/gorm"
+)
 
 type Platform struct {
 	ID   uint   `gorm:"primaryKey"`
 	Name string `gorm:"unique"`
+	// Rating scale the platform publishes scores on, e.g. 1–10 in steps of
+	// 0.1 for Agoda or 1–5 whole stars for Expedia.
+	ScaleMin  float32
+	ScaleMax  float32
+	ScaleStep float32
 }
 
+// Reviewer is the legacy (country, traveler group, room type) combination
+// reviews used to point at. New reviews use the separate dimensions in
+// dimensions.go; the table is kept for the rows migrated from it.
 type Reviewer struct {
 	ID              uint   `gorm:"primaryKey"`
 	CountryName     string `gorm:"uniqueIndex:idx_reviewer_identity"`
@@ -14,17 +30,81 @@ type Reviewer struct {
 	RoomTypeName    string `gorm:"uniqueIndex:idx_reviewer_identity"`
 }
 
+// Composite indexes serve a hotel's reviews filtered by local date or
+// platform; reviewSortIndexes serve them in each sort order.
 type Review struct {
-	ID            uint `gorm:"primaryKey"`
-	HotelID       uint
-	PlatformID    uint
-	ReviewerID    uint
-	HotelReviewID int64 `gorm:"unique"`
-	Rating        float32
-	ReviewTitle   string
-	ReviewText    string
-	ReviewDate    time.Time
-	CreatedAt     time.Time
+	ID            uint    `gorm:"primaryKey"`
+	HotelID       uint    `gorm:"index:idx_reviews_hotel_local_date,priority:1;index:idx_reviews_hotel_platform_date,priority:1"`
+	PlatformID    uint    `gorm:"index:idx_reviews_hotel_platform_date,priority:2"`
+	ListingID     uint    `gorm:"index"`
+	ReviewerID    uint    // legacy, see Reviewer
+	HotelReviewID int64   `gorm:"unique"`
+	Rating        float32 // as published, on the platform's own scale
+	// Reviewer dimensions; nil when the provider did not send the field.
+	CountryID         *uint      `gorm:"index"`
+	TravelerTypeID    *uint      `gorm:"index"`
+	RoomTypeID        *uint      `gorm:"index"`
+	ReviewerProfileID *uint      `gorm:"index"`
+	StayDate          *time.Time // month of the stay, when the platform reports it
+	LengthOfStay      int        // nights, 0 if unknown
+	// Rating mapped onto the common 0–10 scale; every average is built on it.
+	NormalizedRating float32
+	// Title and text as served, with personal data replaced by placeholders
+	// such as [EMAIL].
+	ReviewTitle string
+	ReviewText  string
+	// Comma-separated kinds of personal data redacted, e.g. "email,phone";
+	// NULL for reviews stored before redaction.
+	PIIKinds *string `gorm:"column:pii_kinds"`
+	// Original title and text, encrypted, when the PII policy keeps them.
+	OriginalEncrypted []byte
+	// ReviewDate is nil when the provider sent no date or one we could not
+	// parse; ReviewDateRaw keeps the value as received.
+	ReviewDate    *time.Time `gorm:"index;index:idx_reviews_hotel_platform_date,priority:3"`
+	ReviewDateRaw string
+	// UTC offset in seconds the provider's date carried, nil if it had none.
+	ReviewDateOffset *int
+	// Calendar date at the hotel, in its timezone when set. Analytics bucket
+	// by this date.
+	ReviewLocalDate *time.Time `gorm:"type:date;index;index:idx_reviews_hotel_local_date,priority:2"`
+	// ISO 639-1 code detected from the title and text, "" if undetermined.
+	Language string `gorm:"size:2;index"`
+	// Lexicon sentiment of the title and text, -1 to 1; nil when there is no
+	// lexicon for the review's language.
+	SentimentScore *float32
+	SentimentLabel string `gorm:"size:8;index"` // positive, neutral or negative, "" if unscored
+	// MinHash signature of the text, nil for texts too short to compare.
+	MinHash []byte
+	// Cluster of near-identical reviews this one belongs to, and its
+	// similarity to the closest other member when it joined.
+	DuplicateClusterID  *uint `gorm:"index"`
+	DuplicateSimilarity *float32
+	// How likely the review is spam or part of a manipulation campaign, 0 to
+	// 1, and the comma-separated fraud rules behind it, e.g. "rating_burst".
+	SuspicionScore   float32 `gorm:"index"`
+	SuspicionReasons string
+	// Moderation status, see ModerationVisible, with the reason, actor and
+	// time of the last change.
+	ModerationStatus string `gorm:"size:8;not null;default:visible;index"`
+	ModerationReason string
+	ModeratedBy      string
+	ModeratedAt      *time.Time
+	// Comma-separated data quality rules the review broke but was kept
+	// with, e.g. "empty_text".
+	QualityFlags string
+	CreatedAt    time.Time
+}
+
+// ScoreSentiment sets the review's sentiment from its title and text with the
+// lexicon for its language.
+func (r *Review) ScoreSentiment() {
+	res, ok := sentiment.Score(r.ReviewTitle, r.ReviewText, r.Language)
+	if !ok {
+		r.SentimentScore, r.SentimentLabel = nil, ""
+		return
+	}
+	score := float32(res.Score)
+	r.SentimentScore, r.SentimentLabel = &score, res.Label
 }
 
 type AggregatedHotelReview struct {
@@ -33,3 +113,82 @@ type AggregatedHotelReview struct {
 	AverageRating float32
 	ReviewCount   int
 }
+
+// reviewSortIndexes are the indexes a hotel's reviews are read off in each
+// order the reviews endpoint sorts by, with its directions and NULLS
+// placement; GORM index tags cannot express NULLS LAST.
+var reviewSortIndexes = []struct{ Name, Columns string }{
+	{"idx_reviews_hotel_newest", "hotel_id, review_date DESC NULLS LAST, id DESC"},
+	{"idx_reviews_hotel_oldest", "hotel_id, review_date ASC NULLS LAST, id ASC"},
+	{"idx_reviews_hotel_highest", "hotel_id, normalized_rating DESC, review_date DESC NULLS LAST, id DESC"},
+	{"idx_reviews_hotel_lowest", "hotel_id, normalized_rating ASC, review_date DESC NULLS LAST, id DESC"},
+}
+
+// createReviewSortIndexes creates the missing reviewSortIndexes.
+func createReviewSortIndexes(db *gorm.DB) error {
+	for _, idx := range reviewSortIndexes {
+		if db.Migrator().HasIndex(&Review{}, idx.Name) {
+			continue
+		}
+		log.Printf("↕️  Creating index %s...", idx.Name)
+		if err := db.Exec("CREATE INDEX IF NOT EXISTS " + idx.Name + " ON reviews (" + idx.Columns + ")").Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateReviewDates clears the year-1 dates unparseable values used to be
+// stored as and fills review_local_date. The offsets existing rows were sent
+// with were not kept, so they count as UTC until a replay rewrites them.
+func migrateReviewDates(db *gorm.DB) error {
+	if err := db.Exec(`UPDATE reviews SET review_date = NULL WHERE review_date < '0002-01-01'`).Error; err != nil {
+		return err
+	}
+	return db.Exec(`
+        UPDATE reviews
+        SET review_date_offset = 0, review_local_date = (review_date AT TIME ZONE 'UTC')::date
+        WHERE review_date IS NOT NULL AND review_local_date IS NULL
+    `).Error
+}
+
+// detectExistingLanguages fills language for reviews stored before it was
+// detected during ingestion.
+func detectExistingLanguages(db *gorm.DB) error {
+	var batch []Review
+	return db.Select("id", "review_title", "review_text").Where("language IS NULL OR language = ''").
+		FindInBatches(&batch, 1000, func(tx *gorm.DB, _ int) error {
+			for _, r := range batch {
+				lang, _ := langdetect.Detect(r.ReviewTitle + "\n" + r.ReviewText)
+				if lang == "" {
+					continue
+				}
+				if err := tx.Model(&Review{}).Where("id = ?", r.ID).Update("language", lang).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
+// scoreExistingSentiment scores reviews stored before sentiment was scored
+// during ingestion.
+func scoreExistingSentiment(db *gorm.DB) error {
+	var batch []Review
+	return db.Select("id", "review_title", "review_text", "language").Where("sentiment_label IS NULL OR sentiment_label = ''").
+		FindInBatches(&batch, 1000, func(tx *gorm.DB, _ int) error {
+			for _, r := range batch {
+				r.ScoreSentiment()
+				if r.SentimentScore == nil {
+					continue
+				}
+				if err := tx.Model(&Review{}).Where("id = ?", r.ID).Updates(map[string]interface{}{
+					"sentiment_score": *r.SentimentScore,
+					"sentiment_label": r.SentimentLabel,
+				}).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Management response sources.
+const (
+	ResponseSourcePlatform = "platform" // published reply ingested from the provider
+	ResponseSourceLocal    = "local"    // draft written through our API
+)
+
+// ManagementResponse is the property's reply to a review. A review has at
+// most one response per source, so a local draft can sit next to the reply
+// the platform already shows.
+type ManagementResponse struct {
+	ID       uint   `gorm:"primaryKey"`
+	ReviewID uint   `gorm:"uniqueIndex:idx_review_response"`
+	Source   string `gorm:"uniqueIndex:idx_review_response"`
+	Author   string
+	Text     string
+	// Comma-separated kinds of personal data redacted from Text; NULL for
+	// responses stored before redaction.
+	PIIKinds *string `gorm:"column:pii_kinds"`
+	// When the reply was posted; nil if the platform sent no usable date.
+	ResponseDate *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (ManagementResponse) TableName() string {
+	return "review_responses"
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// searchConfigs maps review languages to the Postgres text search
+// configuration their words are stemmed with, so "shuttles" finds "shuttle".
+// Other languages, and undetermined ones, use simple, which only lowercases.
+// The search vector is generated from this mapping; changing it means
+// dropping the column so it is generated again.
+var searchConfigs = map[string]string{
+	"de": "german",
+	"en": "english",
+	"es": "spanish",
+	"fr": "french",
+	"id": "indonesian",
+	"it": "italian",
+	"nl": "dutch",
+	"pt": "portuguese",
+	"tr": "turkish",
+}
+
+// SearchConfig returns the text search configuration for a language code.
+func SearchConfig(lang string) string {
+	if cfg, ok := searchConfigs[lang]; ok {
+		return cfg
+	}
+	return "simple"
+}
+
+// SearchConfigs lists every configuration reviews are indexed with.
+func SearchConfigs() []string {
+	seen := map[string]bool{"simple": true}
+	configs := []string{"simple"}
+	for _, cfg := range searchConfigs {
+		if !seen[cfg] {
+			seen[cfg] = true
+			configs = append(configs, cfg)
+		}
+	}
+	sort.Strings(configs)
+	return configs
+}
+
+// SearchMatch is a condition on reviews aliased r that their search vector
+// matches a web search query in the review's own language, for reviews
+// indexed with one of configs. Each configuration is matched by name rather
+// than looked up per review, so the GIN index on search_vector serves the
+// match. The query is bound once per configuration, to the returned number
+// of placeholders.
+func SearchMatch(configs []string) (string, int) {
+	mapped := sortedLanguages(func(string) bool { return true })
+	var parts []string
+	for _, cfg := range configs {
+		var langs string
+		if cfg == "simple" {
+			langs = "COALESCE(r.language, '') NOT IN ('" + strings.Join(mapped, "', '") + "')"
+		} else {
+			langs = "r.language IN ('" + strings.Join(sortedLanguages(func(c string) bool { return c == cfg }), "', '") + "')"
+		}
+		parts = append(parts, "("+langs+" AND r.search_vector @@ websearch_to_tsquery('"+cfg+"', ?))")
+	}
+	return "(" + strings.Join(parts, " OR ") + ")", len(parts)
+}
+
+// sortedLanguages returns the mapped languages whose configuration keep
+// accepts, sorted.
+func sortedLanguages(keep func(cfg string) bool) []string {
+	var langs []string
+	for lang, cfg := range searchConfigs {
+		if keep(cfg) {
+			langs = append(langs, lang)
+		}
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// SearchConfigOf is an expression for the text search configuration of the
+// language in column.
+func SearchConfigOf(column string) string {
+	langs := sortedLanguages(func(string) bool { return true })
+
+	var b strings.Builder
+	b.WriteString("CASE " + column)
+	for _, lang := range langs {
+		b.WriteString(" WHEN '" + lang + "' THEN '" + searchConfigs[lang] + "'::regconfig")
+	}
+	b.WriteString(" ELSE 'simple'::regconfig END")
+	return b.String()
+}
+
+// migrateReviewSearch adds the generated search_vector column, the title
+// weighted above the text, and its GIN index. Postgres fills it for existing
+// reviews and keeps it up to date on every write.
+func migrateReviewSearch(db *gorm.DB) error {
+	cfg := SearchConfigOf("language")
+	if err := db.Exec(`
+        ALTER TABLE reviews ADD COLUMN IF NOT EXISTS search_vector tsvector
+        GENERATED ALWAYS AS (
+            setweight(to_tsvector(` + cfg + `, COALESCE(review_title, '')), 'A') ||
+            setweight(to_tsvector(` + cfg + `, COALESCE(review_text, '')), 'B')
+        ) STORED
+    `).Error; err != nil {
+		return err
+	}
+	return db.Exec("CREATE INDEX IF NOT EXISTS idx_reviews_search ON reviews USING GIN (search_vector)").Error
+}
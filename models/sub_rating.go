@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Rating aspects providers score separately from the overall rating.
+const (
+	AspectCleanliness = "cleanliness"
+	AspectLocation    = "location"
+	AspectStaff       = "staff"
+	AspectValue       = "value"
+	AspectFacilities  = "facilities"
+)
+
+// Aspects lists every aspect in display order.
+var Aspects = []string{AspectCleanliness, AspectLocation, AspectStaff, AspectValue, AspectFacilities}
+
+// ReviewSubRating is a review's score for one aspect, kept on the platform's
+// scale and on the normalized 0–10 scale like Review.Rating.
+type ReviewSubRating struct {
+	ID               uint   `gorm:"primaryKey"`
+	ReviewID         uint   `gorm:"uniqueIndex:idx_sub_rating"`
+	Aspect           string `gorm:"uniqueIndex:idx_sub_rating"`
+	Rating           float32
+	NormalizedRating float32
+}
+
+// HotelAspectSummary holds a hotel's pre-aggregated score for one aspect,
+// maintained alongside HotelRatingsSummary on the normalized scale. Not
+// every review scores every aspect, so each aspect has its own count.
+type HotelAspectSummary struct {
+	HotelID       uint    `gorm:"primaryKey"`
+	Aspect        string  `gorm:"primaryKey"`
+	TotalRatings  int     `gorm:"default:0"`
+	TotalRating   float64 `gorm:"default:0"`
+	AverageRating float64
+	LastUpdated   time.Time
+}
+
+// RecomputeAspectSummaries rebuilds the per-aspect summaries of the given
+// hotels, or of every hotel when none are given, from the sub-ratings of
+// their visible reviews.
+func RecomputeAspectSummaries(db *gorm.DB, hotelIDs ...uint) error {
+	del := db.Where("1 = 1")
+	filter := "WHERE " + VisibleOnly
+	args := []interface{}{}
+	if len(hotelIDs) > 0 {
+		del = db.Where("hotel_id IN ?", hotelIDs)
+		filter += " AND r.hotel_id IN ?"
+		args = append(args, hotelIDs)
+	}
+	if err := del.Delete(&HotelAspectSummary{}).Error; err != nil {
+		return err
+	}
+	return db.Exec(`
+        INSERT INTO hotel_aspect_summaries (hotel_id, aspect, total_ratings, total_rating, average_rating, last_updated)
+        SELECT r.hotel_id, s.aspect, COUNT(*), SUM(s.normalized_rating), AVG(s.normalized_rating), NOW()
+        FROM review_sub_ratings s
+        JOIN reviews r ON r.id = s.review_id
+        `+filter+`
+        GROUP BY r.hotel_id, s.aspect
+    `, args...).Error
+}
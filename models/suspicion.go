@@ -0,0 +1,244 @@
+package models
+
+import (
+	"database/sql"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"review-system/internal/fraud"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// suspicionWindow is how far around a review's date the hotel's other
+	// reviews are compared with it.
+	suspicionWindow = 30 * 24 * time.Hour
+	// baselineDays is how many days before a review set the hotel's usual
+	// daily volume, at most.
+	baselineDays = 28
+)
+
+var (
+	suspicionThresholdOnce sync.Once
+	suspicionThreshold     float64
+)
+
+// SuspicionThreshold is the score from which a review counts as suspicious,
+// fraud.DefaultThreshold unless SUSPICION_THRESHOLD is set.
+func SuspicionThreshold() float64 {
+	suspicionThresholdOnce.Do(func() {
+		suspicionThreshold = fraud.DefaultThreshold
+		if v := os.Getenv("SUSPICION_THRESHOLD"); v != "" {
+			if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 && f <= 1 {
+				suspicionThreshold = f
+			} else {
+				log.Printf("⚠️  Ignoring SUSPICION_THRESHOLD %q: want a number in (0, 1]", v)
+			}
+		}
+	})
+	return suspicionThreshold
+}
+
+// ScoreSuspicion scores a stored review with the fraud rules and stores the
+// score and reasons. A review can complete a pattern, such as the fifth
+// review of a burst or the third copy of a text, so the reviews sharing a
+// pattern it shows are scored again if they do not show it yet.
+func ScoreSuspicion(tx *gorm.DB, review *Review) error {
+	if err := scoreSuspicion(tx, review); err != nil {
+		return err
+	}
+
+	for _, reason := range strings.Split(review.SuspicionReasons, ",") {
+		query := tx.Where("id <> ? AND hotel_id = ? AND suspicion_reasons NOT LIKE ?",
+			review.ID, review.HotelID, "%"+reason+"%")
+		switch reason {
+		case fraud.RatingBurst:
+			query = query.Where("review_local_date = ? AND (normalized_rating <= ? OR normalized_rating >= ?)",
+				review.ReviewLocalDate, fraud.ExtremeLow, fraud.ExtremeHigh)
+		case fraud.TemplateText:
+			if review.DuplicateClusterID != nil {
+				query = query.Where("(LOWER(TRIM(review_text)) = LOWER(TRIM(?)) OR duplicate_cluster_id = ?)",
+					review.ReviewText, *review.DuplicateClusterID)
+			} else {
+				query = query.Where("LOWER(TRIM(review_text)) = LOWER(TRIM(?))", review.ReviewText)
+			}
+		case fraud.RepeatReviewer:
+			query = query.Where("reviewer_profile_id = ?", review.ReviewerProfileID)
+		default:
+			continue
+		}
+
+		var related []Review
+		if err := query.Find(&related).Error; err != nil {
+			return err
+		}
+		for i := range related {
+			if err := scoreSuspicion(tx, &related[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RescoreNeighbours scores again the reviews of review's hotel that shared
+// a pattern with it, once it no longer counts towards them because it was
+// hidden or deleted. review is as it was before the change.
+func RescoreNeighbours(tx *gorm.DB, review Review) error {
+	var conds []string
+	var args []interface{}
+	if review.ReviewLocalDate != nil {
+		conds = append(conds, "review_local_date = ?")
+		args = append(args, review.ReviewLocalDate)
+	}
+	if len(strings.Fields(review.ReviewText)) >= fraud.TemplateMinWords {
+		conds = append(conds, "LOWER(TRIM(review_text)) = LOWER(TRIM(?))")
+		args = append(args, review.ReviewText)
+	}
+	if review.DuplicateClusterID != nil {
+		conds = append(conds, "duplicate_cluster_id = ?")
+		args = append(args, *review.DuplicateClusterID)
+	}
+	if review.ReviewerProfileID != nil {
+		conds = append(conds, "reviewer_profile_id = ?")
+		args = append(args, *review.ReviewerProfileID)
+	}
+	if len(conds) == 0 {
+		return nil
+	}
+
+	var related []Review
+	if err := tx.Where("hotel_id = ? AND id <> ?", review.HotelID, review.ID).
+		Where("("+strings.Join(conds, " OR ")+")", args...).Find(&related).Error; err != nil {
+		return err
+	}
+	for i := range related {
+		if err := scoreSuspicion(tx, &related[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RescoreHotels scores every review of the given hotels again, after
+// reviews moved between them.
+func RescoreHotels(tx *gorm.DB, hotelIDs ...uint) error {
+	var batch []Review
+	return tx.Where("hotel_id IN ?", hotelIDs).FindInBatches(&batch, 1000, func(btx *gorm.DB, _ int) error {
+		for i := range batch {
+			if err := scoreSuspicion(btx, &batch[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+func scoreSuspicion(tx *gorm.DB, review *Review) error {
+	s, err := suspicionSignals(tx, *review)
+	if err != nil {
+		return err
+	}
+	score, reasons := fraud.Score(s)
+	review.SuspicionScore = float32(score)
+	review.SuspicionReasons = strings.Join(reasons, ",")
+	return tx.Model(&Review{}).Where("id = ?", review.ID).Updates(map[string]interface{}{
+		"suspicion_score":   review.SuspicionScore,
+		"suspicion_reasons": review.SuspicionReasons,
+	}).Error
+}
+
+// suspicionSignals gathers what the fraud rules need to know about a review
+// and the hotel's reviews around it. Hidden reviews do not count towards a
+// pattern, so hiding one clears the patterns it completed.
+func suspicionSignals(tx *gorm.DB, r Review) (fraud.Signals, error) {
+	s := fraud.Signals{
+		NormalizedRating: r.NormalizedRating,
+		TextWords:        len(strings.Fields(r.ReviewText)),
+	}
+	count := func(n *int, query string, args ...interface{}) error {
+		var c int64
+		err := tx.Model(&Review{}).Where(query, args...).
+			Where("moderation_status = ? OR id = ?", ModerationVisible, r.ID).Count(&c).Error
+		*n = int(c)
+		return err
+	}
+
+	// Only reviews dated near this one are compared with it.
+	window := "TRUE"
+	var windowArgs []interface{}
+	if r.ReviewDate != nil {
+		window = "review_date BETWEEN ? AND ?"
+		windowArgs = []interface{}{r.ReviewDate.Add(-suspicionWindow), r.ReviewDate.Add(suspicionWindow)}
+	}
+
+	if r.ReviewLocalDate != nil {
+		if err := count(&s.DayReviews, "hotel_id = ? AND review_local_date = ?", r.HotelID, r.ReviewLocalDate); err != nil {
+			return s, err
+		}
+		// A hotel reviewed for less than the baseline period is averaged over
+		// the days it has, so its first days do not all look like bursts.
+		var first sql.NullTime
+		if err := tx.Model(&Review{}).Select("MIN(review_local_date)").
+			Where("hotel_id = ? AND (moderation_status = ? OR id = ?)", r.HotelID, ModerationVisible, r.ID).
+			Row().Scan(&first); err != nil {
+			return s, err
+		}
+		if first.Valid {
+			s.HistoryDays = min(baselineDays, int(r.ReviewLocalDate.Sub(first.Time).Hours()/24))
+		}
+		if s.HistoryDays > 0 {
+			var before int
+			if err := count(&before, "hotel_id = ? AND review_local_date >= ? AND review_local_date < ?",
+				r.HotelID, r.ReviewLocalDate.AddDate(0, 0, -s.HistoryDays), r.ReviewLocalDate); err != nil {
+				return s, err
+			}
+			s.BaselineDaily = float64(before) / float64(s.HistoryDays)
+		}
+	}
+
+	if s.TextWords >= fraud.TemplateMinWords {
+		if err := count(&s.IdenticalTexts, "hotel_id = ? AND id <> ? AND LOWER(TRIM(review_text)) = LOWER(TRIM(?)) AND "+window,
+			append([]interface{}{r.HotelID, r.ID, r.ReviewText}, windowArgs...)...); err != nil {
+			return s, err
+		}
+	}
+	if r.DuplicateClusterID != nil {
+		if err := count(&s.ClusterSize, "duplicate_cluster_id = ?", *r.DuplicateClusterID); err != nil {
+			return s, err
+		}
+	}
+
+	if r.ReviewerProfileID != nil {
+		var profile ReviewerProfile
+		if err := tx.Select("review_count").First(&profile, *r.ReviewerProfileID).Error; err != nil && err != gorm.ErrRecordNotFound {
+			return s, err
+		}
+		s.ReviewerReviews = profile.ReviewCount
+		if err := count(&s.ReviewerHotelReviews, "reviewer_profile_id = ? AND hotel_id = ? AND "+window,
+			append([]interface{}{*r.ReviewerProfileID, r.HotelID}, windowArgs...)...); err != nil {
+			return s, err
+		}
+	}
+	return s, nil
+}
+
+// scoreExistingSuspicion scores reviews stored before suspicion was scored
+// during ingestion. Every review is already stored, so patterns are seen in
+// full and nothing needs scoring twice.
+func scoreExistingSuspicion(db *gorm.DB) error {
+	var batch []Review
+	return db.Order("id").FindInBatches(&batch, 1000, func(tx *gorm.DB, _ int) error {
+		for i := range batch {
+			if err := scoreSuspicion(tx, &batch[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
@@ -1,20 +1,273 @@
 package models
 
+import "time"
+
 type ErrorResponse struct {
 	Error string `json:"error"`
 }
 
 type ReviewDetail struct {
-	Rating          float32 `json:"rating"`
-	ReviewTitle     string  `json:"review_title"`
-	ReviewText      string  `json:"review_text"`
-	ReviewDate      string  `json:"review_date"`
-	CountryName     string  `json:"country_name"`
-	ReviewGroupName string  `json:"review_group_name"`
-	RoomTypeName    string  `json:"room_type_name"`
+	ID                 uint              `json:"id"`
+	Rating             float32           `json:"rating"`
+	NormalizedRating   float32           `json:"normalized_rating"`
+	RatingScaleMax     float32           `json:"rating_scale_max"`
+	Platform           string            `json:"platform"`
+	ReviewTitle        string            `json:"review_title"`
+	ReviewText         string            `json:"review_text"`
+	ReviewDate         *string           `json:"review_date"`          // null if the provider's date was missing or unparseable
+	ReviewLocalDate    *string           `json:"review_local_date"`    // YYYY-MM-DD at the hotel
+	Language           string            `json:"language"`             // ISO 639-1, "" if undetermined
+	SentimentScore     *float32          `json:"sentiment_score"`      // -1 to 1, null if unscored
+	SentimentLabel     string            `json:"sentiment_label"`      // positive, neutral or negative
+	DuplicateClusterID *uint             `json:"duplicate_cluster_id"` // null unless near-duplicates were found
+	SuspicionScore     float32           `json:"suspicion_score"`      // 0 to 1
+	SuspicionReasons   string            `json:"suspicion_reasons"`    // comma-separated fraud rules, "" if none fired
+	CountryName        string            `json:"country_name"`
+	CountryCode        *string           `json:"country_code"` // ISO 3166-1 alpha-2, null if unrecognized
+	ReviewGroupName    string            `json:"review_group_name"`
+	RoomTypeName       string            `json:"room_type_name"`
+	ReviewerName       *string           `json:"reviewer_name"`
+	StayDate           *string           `json:"stay_date"`
+	LengthOfStay       int               `json:"length_of_stay"`
+	SubRatings         []SubRatingDetail `json:"sub_ratings"`
+	Response           *ResponseDetail   `json:"response"`
+}
+
+// SentimentMismatch is a review whose text sentiment disagrees with its
+// rating.
+type SentimentMismatch struct {
+	ID               uint     `json:"id"`
+	HotelReviewID    int64    `json:"hotel_review_id"`
+	HotelID          uint     `json:"hotel_id"`
+	HotelName        string   `json:"hotel_name"`
+	Platform         string   `json:"platform"`
+	Rating           float32  `json:"rating"`
+	NormalizedRating float32  `json:"normalized_rating"`
+	ReviewTitle      string   `json:"review_title"`
+	ReviewText       string   `json:"review_text"`
+	Language         string   `json:"language"`
+	SentimentScore   *float32 `json:"sentiment_score"`
+	SentimentLabel   string   `json:"sentiment_label"`
+	// negative_text_high_rating or positive_text_low_rating
+	Mismatch string `json:"mismatch"`
+}
+
+type SentimentMismatchReport struct {
+	HighRating float32             `json:"high_rating"`
+	LowRating  float32             `json:"low_rating"`
+	Total      int64               `json:"total"`
+	Page       int                 `json:"page"`
+	Limit      int                 `json:"limit"`
+	Reviews    []SentimentMismatch `json:"reviews"`
+}
+
+// SuspiciousReview is a review the fraud rules scored at or above the
+// requested suspicion score.
+type SuspiciousReview struct {
+	ID                 uint       `json:"id"`
+	HotelReviewID      int64      `json:"hotel_review_id"`
+	HotelID            uint       `json:"hotel_id"`
+	HotelName          string     `json:"hotel_name"`
+	Platform           string     `json:"platform"`
+	Rating             float32    `json:"rating"`
+	NormalizedRating   float32    `json:"normalized_rating"`
+	ReviewTitle        string     `json:"review_title"`
+	ReviewText         string     `json:"review_text"`
+	ReviewDate         *time.Time `json:"review_date"`
+	ReviewerProfileID  *uint      `json:"reviewer_profile_id"`
+	DuplicateClusterID *uint      `json:"duplicate_cluster_id"`
+	SuspicionScore     float32    `json:"suspicion_score"` // 0 to 1
+	SuspicionReasons   string     `json:"-"`
+	Reasons            []string   `json:"suspicion_reasons" gorm:"-"`
+}
+
+type SuspiciousReviewReport struct {
+	MinScore float32            `json:"min_score"`
+	Total    int64              `json:"total"`
+	Page     int                `json:"page"`
+	Limit    int                `json:"limit"`
+	Reviews  []SuspiciousReview `json:"reviews"`
+}
+
+type ModerationActionPage struct {
+	Total   int64              `json:"total"`
+	Page    int                `json:"page"`
+	Limit   int                `json:"limit"`
+	Actions []ModerationAction `json:"actions"`
+}
+
+// ReviewSearchHit is a review matching a full-text search.
+type ReviewSearchHit struct {
+	ID               uint       `json:"id"`
+	HotelID          uint       `json:"hotel_id"`
+	HotelName        string     `json:"hotel_name"`
+	Platform         string     `json:"platform"`
+	Rating           float32    `json:"rating"`
+	NormalizedRating float32    `json:"normalized_rating"`
+	ReviewDate       *time.Time `json:"review_date"`
+	Language         string     `json:"language"`
+	TitleHighlight   string     `json:"title_highlight"` // HTML, matches in <mark>
+	Snippet          string     `json:"snippet"`         // HTML fragments of the text around the matches
+	Rank             float64    `json:"rank"`
+}
+
+type ReviewSearchResults struct {
+	Query   string            `json:"query"`
+	Total   int64             `json:"total"`
+	Page    int               `json:"page"`
+	Limit   int               `json:"limit"`
+	Results []ReviewSearchHit `json:"results"`
+}
+
+// ReviewOriginal is a review's text as the provider sent it.
+type ReviewOriginal struct {
+	ID            uint     `json:"id"`
+	HotelReviewID int64    `json:"hotel_review_id"`
+	ReviewTitle   string   `json:"review_title"`
+	ReviewText    string   `json:"review_text"`
+	PIIKinds      []string `json:"pii_kinds"` // kinds of personal data redacted from the served text
+}
+
+type LanguageCount struct {
+	Language    string `json:"language"` // ISO 639-1, or und
+	ReviewCount int    `json:"review_count"`
+}
+
+type ResponseDetail struct {
+	Source       string     `json:"source"` // platform or local
+	Author       string     `json:"author"`
+	Text         string     `json:"text"`
+	ResponseDate *time.Time `json:"response_date"` // null if the platform sent no usable date
+}
+
+type ResponseMetrics struct {
+	TotalReviews        int      `json:"total_reviews"`
+	RespondedReviews    int      `json:"responded_reviews"`
+	ResponseRate        float64  `json:"response_rate" gorm:"-"` // 0–1
+	MedianResponseHours *float64 `json:"median_response_hours"`
+}
+
+type SubRatingDetail struct {
+	Aspect           string  `json:"aspect"`
+	Rating           float32 `json:"rating"`
+	NormalizedRating float32 `json:"normalized_rating"`
+}
+
+type AspectRating struct {
+	Aspect        string  `json:"aspect"`
+	TotalRatings  int     `json:"total_ratings"`
+	AverageRating float64 `json:"average_rating"`
+}
+
+// HotelListItem is a hotel with its pre-aggregated ratings.
+type HotelListItem struct {
+	ID            uint       `json:"id"`
+	Name          string     `json:"name"`
+	Address       string     `json:"address"`
+	Timezone      string     `json:"timezone"`
+	TotalReviews  int        `json:"total_reviews"`
+	AverageRating float64    `json:"average_rating"` // normalized 0–10
+	LastUpdated   *time.Time `json:"last_updated"`   // null until the hotel has a summary
+	Platforms     []string   `json:"platforms" gorm:"-"`
+}
+
+// ResolvedHotel maps a platform's hotel ID to our hotel, null when we have
+// no listing for it.
+type ResolvedHotel struct {
+	Platform   string         `json:"platform"`
+	ExternalID int            `json:"external_id"`
+	HotelID    *uint          `json:"hotel_id"`
+	Hotel      *HotelListItem `json:"hotel"`
+}
+
+type HotelList struct {
+	Total  int64           `json:"total"`
+	Page   int             `json:"page"`
+	Limit  int             `json:"limit"`
+	Hotels []HotelListItem `json:"hotels"`
+}
+
+type RatingsBreakdown struct {
+	HotelID       uint           `json:"hotel_id"`
+	TotalReviews  int            `json:"total_reviews"`
+	AverageRating float64        `json:"average_rating"`
+	Aspects       []AspectRating `json:"aspects"`
 }
 
 type ReviewResponse struct {
-	Hotel   AggregatedHotelReview `json:"hotel"`
-	Reviews []ReviewDetail        `json:"reviews"`
+	Hotel      AggregatedHotelReview `json:"hotel"`
+	Aspects    []AspectRating        `json:"aspects"`
+	Languages  []LanguageCount       `json:"languages"`
+	Responses  ResponseMetrics       `json:"responses"`
+	Pagination ReviewPagination      `json:"pagination"`
+	Reviews    []ReviewDetail        `json:"reviews"`
+}
+
+type ReviewPagination struct {
+	Page       int     `json:"page,omitempty"` // omitted when paging by cursor
+	Limit      int     `json:"limit"`
+	HasMore    bool    `json:"has_more"`
+	NextCursor *string `json:"next_cursor"`     // pass as cursor for the next page, null on the last
+	Total      *int64  `json:"total,omitempty"` // with include_total only
+}
+
+type RuleViolationCount struct {
+	Rule   string `json:"rule"`
+	Action string `json:"action"`
+	Count  int64  `json:"count"`
+}
+
+type DataQualityDay struct {
+	Day            string               `json:"day"`
+	Platform       string               `json:"platform"`
+	Reviews        int64                `json:"reviews"`         // stored that day
+	FlaggedReviews int64                `json:"flagged_reviews"` // stored with quality flags
+	Rejected       int64                `json:"rejected"`
+	Quarantined    int64                `json:"quarantined"`
+	Violations     []RuleViolationCount `json:"violations"`
+}
+
+type QualityRuleInfo struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Action      string            `json:"action"`
+	Platforms   map[string]string `json:"platforms"` // per-platform action overrides
+}
+
+type DataQualityReport struct {
+	Rules []QualityRuleInfo `json:"rules"`
+	Days  []DataQualityDay  `json:"days"`
+}
+
+// AspectSentiment is how a hotel's reviews talk about one aspect.
+type AspectSentiment struct {
+	Aspect   string `json:"aspect"`
+	Mentions int    `json:"mentions"`
+	Positive int    `json:"positive"`
+	Neutral  int    `json:"neutral"`
+	Negative int    `json:"negative"`
+	// (positive - negative) / mentions, -1 to 1
+	NetSentiment float64 `json:"net_sentiment" gorm:"-"`
+	AverageScore float64 `json:"average_score"`
+	// The latest sentences complaining about the aspect.
+	Complaints []string `json:"complaints" gorm:"-"`
+}
+
+type HotelAspectSentiment struct {
+	HotelID uint              `json:"hotel_id"`
+	From    string            `json:"from"` // YYYY-MM-DD at the hotel
+	To      string            `json:"to"`
+	Aspects []AspectSentiment `json:"aspects"`
+}
+
+type DailyRating struct {
+	Day           string  `json:"day"` // YYYY-MM-DD at the hotel
+	ReviewCount   int     `json:"review_count"`
+	AverageRating float64 `json:"average_rating"`
+}
+
+type RatingTrend struct {
+	HotelID  uint          `json:"hotel_id"`
+	Timezone string        `json:"timezone"` // empty if the hotel has none set
+	Days     []DailyRating `json:"days"`
 }
@@ -8,7 +8,33 @@ import (
 )
 
 func SetupRoutesWith(e *echo.Echo) {
+	e.GET("/hotels", handlers.ListHotels)
+	e.GET("/reviews/search", handlers.SearchReviews)
 	e.GET("/hotels/:id/reviews", handlers.GetHotelReviews)
+	e.GET("/hotels/:id/ratings/breakdown", handlers.GetRatingsBreakdown)
+	e.GET("/hotels/:id/ratings/trend", handlers.GetRatingTrend)
+	e.GET("/hotels/:id/aspects", handlers.GetHotelAspects)
+	e.GET("/hotels/:id/reviews/:reviewId/response", handlers.GetReviewResponses, handlers.RequireRole(handlers.RoleResponder))
+	e.POST("/hotels/:id/reviews/:reviewId/response", handlers.SaveReviewResponse, handlers.RequireRole(handlers.RoleResponder))
+	e.GET("/platforms/:platform/hotels/:externalId", handlers.GetPlatformHotel)
+	e.GET("/platforms/:platform/hotels/:externalId/reviews", handlers.GetPlatformHotelReviews)
+	e.POST("/platforms/:platform/hotels/resolve", handlers.ResolvePlatformHotels)
+
+	admin := e.Group("/admin")
+	admin.GET("/hotel-matches", handlers.ListHotelMatches, handlers.RequireRole(handlers.RoleHotelAdmin))
+	admin.POST("/hotel-matches/:id/confirm", handlers.ConfirmHotelMatch, handlers.RequireRole(handlers.RoleHotelAdmin))
+	admin.POST("/hotel-matches/:id/reject", handlers.RejectHotelMatch, handlers.RequireRole(handlers.RoleHotelAdmin))
+	admin.GET("/hotels/:id/listings", handlers.GetHotelListings, handlers.RequireRole(handlers.RoleHotelAdmin))
+	admin.POST("/hotels/:id/merge", handlers.MergeHotels, handlers.RequireRole(handlers.RoleHotelAdmin))
+	admin.POST("/hotels/:id/split", handlers.SplitHotel, handlers.RequireRole(handlers.RoleHotelAdmin))
+	admin.PUT("/hotels/:id/timezone", handlers.SetHotelTimezone, handlers.RequireRole(handlers.RoleHotelAdmin))
+	admin.GET("/data-quality", handlers.GetDataQuality, handlers.RequireRole(handlers.RoleAnalyst))
+	admin.GET("/sentiment-mismatches", handlers.GetSentimentMismatches, handlers.RequireRole(handlers.RoleAnalyst))
+	admin.GET("/suspicious-reviews", handlers.GetSuspiciousReviews, handlers.RequireRole(handlers.RoleModerator))
+	admin.GET("/reviews/:id/original", handlers.GetReviewOriginal, handlers.RequireRole(handlers.RolePIIReader))
+	admin.POST("/reviews/moderation", handlers.ModerateReviews, handlers.RequireRole(handlers.RoleModerator))
+	admin.GET("/moderation-actions", handlers.GetModerationActions, handlers.RequireRole(handlers.RoleModerator))
+
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 	e.Logger.Fatal(e.Start(":8080"))
 }